@@ -1,10 +1,17 @@
 package core
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"iter"
 	"log/slog"
 	"net/url"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
 )
 
 // ActionConfig is the minimal contract every concrete configuration must
@@ -21,9 +28,9 @@ type ActionConfig interface {
 	GetCertPaths() []string
 	// path to client certificate
 	ClientCertPaths() (cert, key string)
-	// secret DSNs
-	//
-	// TODO: two engines with one protocol? like vault-1:// and vault-2://?
+	// secret DSNs keyed by storage name. Backend is chosen by DSN scheme, so
+	// several storages may share it, e.g. "vault-primary" and "vault-backup"
+	// both pointing to vault:// DSNs. Secrets are addressed as "name:key".
 	GetSecretDSNs() map[string]*url.URL
 	// OTEL trace endpoint
 	GetTraceEndpoint() *url.URL
@@ -31,6 +38,61 @@ type ActionConfig interface {
 	GetMetricsAddr() *url.URL
 }
 
+// FeaturesConfig is an optional extension of [ActionConfig] for applications
+// that evaluate feature flags. It is probed at runtime, so configurations that
+// do not need feature flags are not forced to implement it.
+type FeaturesConfig interface {
+	ActionConfig
+
+	// OpenFeature provider to evaluate flags against. If nil, feature flags
+	// are disabled and [Features] reports false.
+	GetFeatureProvider() openfeature.FeatureProvider
+}
+
+// SecretsConfig is an optional extension of [ActionConfig] for applications
+// keeping the list of secret DSNs in a file, e.g. mounted as a secret itself,
+// instead of passing every DSN through environment.
+type SecretsConfig interface {
+	ActionConfig
+
+	// path to file with "name=DSN" lines, augmenting
+	// [ActionConfig.GetSecretDSNs]. Empty path means no file.
+	GetSecretDSNFile() string
+}
+
+// EnvironmentConfig is an optional extension of [ActionConfig] for
+// applications tagging their telemetry with deployment environment.
+type EnvironmentConfig interface {
+	ActionConfig
+
+	// deployment environment, e.g. "prod", "staging" or "dev". Empty value
+	// means no tag.
+	GetEnvironment() string
+}
+
+// ShutdownConfig is an optional extension of [ActionConfig] for applications
+// declaring their graceful shutdown budget, e.g. to drain long requests.
+type ShutdownConfig interface {
+	ActionConfig
+
+	// total time to shut down acquired params after the action returns.
+	// Non-positive value means runtime default.
+	GetShutdownTimeout() time.Duration
+}
+
+// ValidatableConfig is an optional extension of [ActionConfig] for
+// applications checking parsed configuration (e.g. mutually exclusive or
+// malformed settings) before anything is set up, so it fails fast instead of
+// deep in acquisition. Secret fields are resolved only after validation, once
+// secret engine is built.
+type ValidatableConfig interface {
+	ActionConfig
+
+	// Validate reports invalid configuration. Several problems may be joined
+	// with [errors.Join], each one is reported separately.
+	Validate(ctx context.Context) error
+}
+
 // UnsafeActionConfig is an empty opt-in marker that satisfies [ActionConfig]
 // via embedding. Use it when quickly scaffolding a config type; replace with
 // explicit methods as requirements grow.
@@ -126,6 +188,97 @@ func Stdin[T ActionConfig](ctx AppContext[T]) (io.Reader, bool) {
 	return nil, false
 }
 
+// BufferedStdin wraps the pipeline stdin into a [bufio.Reader] of the given
+// size when input is piped (non-positive size means 4 KiB).
+// Interactive stdin is wrapped with the smallest buffer bufio allows instead,
+// so reading doesn't consume much more user input than requested.
+//
+// Stdin is considered piped, unless context reports otherwise with
+// IsPipeline() bool method, see [Pipeline.IsPipeline].
+//
+// Returns (nil, false) if the context has no pipeline capability.
+func BufferedStdin[T ActionConfig](ctx AppContext[T], size int) (*bufio.Reader, bool) {
+	v, ok := ctx.(PipelineAppContext[T])
+	if !ok {
+		return nil, false
+	}
+
+	if p, ok := ctx.(interface{ IsPipeline() bool }); ok && !p.IsPipeline() {
+		size = 0 // bufio picks its minimal size.
+	} else if size <= 0 {
+		size = defaultStdinBufferSize
+	}
+
+	return bufio.NewReaderSize(v.Stdin(), size), true
+}
+
+const defaultStdinBufferSize = 4096
+
+// Lines returns a sequence over lines read from the pipeline stdin, see
+// [LineReader]. Line terminators are stripped. A read error (or ctx
+// cancellation between lines) is yielded once with an empty line, then the
+// sequence ends.
+//
+// Returns (nil, false) if the context has no pipeline capability.
+func Lines[T ActionConfig](ctx context.Context, appCtx AppContext[T]) (iter.Seq2[string, error], bool) {
+	scanner, ok := LineReader(appCtx)
+	if !ok {
+		return nil, false
+	}
+
+	return func(yield func(string, error) bool) {
+		for scanner.Scan() {
+			if err := ctx.Err(); err != nil {
+				yield("", err)
+				return
+			}
+
+			if !yield(scanner.Text(), nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield("", fmt.Errorf("reading stdin: %w", err))
+		}
+	}, true
+}
+
+// ErrNoStdin is returned by [ReadAll] if the context has no pipeline
+// capability.
+var ErrNoStdin = errors.New("stdin is not available")
+
+// LineReader returns a [bufio.Scanner] over the pipeline stdin. Runtime fails
+// stdin reads once the action context is cancelled, so [bufio.Scanner.Err]
+// reports the cancellation.
+//
+// Returns (nil, false) if the context has no pipeline capability.
+func LineReader[T ActionConfig](ctx AppContext[T]) (*bufio.Scanner, bool) {
+	stdin, ok := Stdin(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	return bufio.NewScanner(stdin), true
+}
+
+// ReadAll reads the pipeline stdin until EOF, or until the action context is
+// cancelled, see [LineReader]. Returns [ErrNoStdin] if the context has no
+// pipeline capability.
+func ReadAll[T ActionConfig](ctx AppContext[T]) ([]byte, error) {
+	stdin, ok := Stdin(ctx)
+	if !ok {
+		return nil, ErrNoStdin
+	}
+
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return data, fmt.Errorf("reading stdin: %w", err)
+	}
+
+	return data, nil
+}
+
 func Stdout[T ActionConfig](ctx AppContext[T]) (io.Writer, bool) {
 	if v, ok := ctx.(PipelineAppContext[T]); ok {
 		return v.Stdout(), ok
@@ -178,3 +331,120 @@ func Observability[T ActionConfig](ctx AppContext[T]) (Metrics, bool) {
 
 	return nil, false
 }
+
+type ReadinessAppContext[T ActionConfig] interface {
+	AppContext[T]
+
+	AddReadyCheck(check func(context.Context) bool)
+}
+
+// AddReadyCheck registers a readiness check of the application, e.g. to
+// report not-ready during cache warmup. Readiness probe succeeds only when
+// every registered check succeeds. Checks are called concurrently on each
+// probe request, so they must be fast and safe for concurrent use.
+//
+// Returns false if the context doesn't support readiness checks.
+func AddReadyCheck[T ActionConfig](ctx AppContext[T], check func(context.Context) bool) bool {
+	if v, ok := ctx.(ReadinessAppContext[T]); ok {
+		v.AddReadyCheck(check)
+
+		return true
+	}
+
+	return false
+}
+
+// HealthRegistry collects named health checks of application components,
+// e.g. database or cache connections.
+type HealthRegistry interface {
+	// RegisterHealthCheck registers check of the component. Check returns
+	// nil if component is healthy. Checks are called on each probe request,
+	// so they must be fast and safe for concurrent use.
+	RegisterHealthCheck(name string, check func(context.Context) error)
+}
+
+type HealthAppContext[T ActionConfig] interface {
+	AppContext[T]
+	HealthRegistry
+}
+
+// Health extracts registry of component health checks from the provided
+// [AppContext]. Unlike [AddReadyCheck], checks are named, so the readiness
+// probe reports which components are failing.
+//
+// Returns (nil, false) if the context doesn't support health checks.
+//
+//nolint:ireturn // returns interface on intention.
+func Health[T ActionConfig](ctx AppContext[T]) (HealthRegistry, bool) {
+	if v, ok := ctx.(HealthAppContext[T]); ok {
+		return v, true
+	}
+
+	return nil, false
+}
+
+type ReadyAppContext[T ActionConfig] interface {
+	AppContext[T]
+
+	// Ready reports that action setup (e.g. registration of gRPC services) is
+	// done, so servers could start serving. Repeated calls are no-op.
+	Ready()
+}
+
+// Ready signals the runtime that action is set up, so [Servable] params are
+// started. Servers never start before the signal: some of them (e.g. gRPC)
+// don't allow registration of handlers once serving. If action never calls
+// it, servers start once action returns with zero exit code.
+//
+// Returns false if the context doesn't support ready signal.
+func Ready[T ActionConfig](ctx AppContext[T]) bool {
+	if v, ok := ctx.(ReadyAppContext[T]); ok {
+		v.Ready()
+
+		return true
+	}
+
+	return false
+}
+
+type FeatureAppContext[T ActionConfig] interface {
+	AppContext[T]
+
+	Features() openfeature.IClient
+}
+
+// Features attempts to extract an OpenFeature client from the provided
+// [AppContext]. Unlike [Observability], there is no no-op fallback: when the
+// configuration does not supply a provider, (nil, false) is returned so
+// callers can explicitly branch on whether feature flags are available.
+//
+//nolint:ireturn // returns interface on intention.
+func Features[T ActionConfig](ctx AppContext[T]) (openfeature.IClient, bool) {
+	if v, ok := ctx.(FeatureAppContext[T]); ok {
+		if client := v.Features(); client != nil {
+			return client, true
+		}
+	}
+
+	return nil, false
+}
+
+type ArgsAppContext[T ActionConfig] interface {
+	AppContext[T]
+
+	// Args returns command line arguments left for the action itself, e.g.
+	// subcommand name and its flags.
+	Args() []string
+}
+
+// Args extracts command line arguments from the provided [AppContext], so
+// action can parse its own flags or dispatch subcommands. Returns (nil, false)
+// if the context has no arguments capability, e.g. it's not run from command
+// line.
+func Args[T ActionConfig](ctx AppContext[T]) ([]string, bool) {
+	if v, ok := ctx.(ArgsAppContext[T]); ok {
+		return v.Args(), true
+	}
+
+	return nil, false
+}
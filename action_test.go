@@ -0,0 +1,250 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	. "github.com/quenbyako/core"
+)
+
+type pipelineAppContext struct {
+	AppContext[UnimplementedActionConfig]
+
+	stdin      io.Reader
+	isPipeline bool
+}
+
+func (c pipelineAppContext) Stdin() io.Reader  { return c.stdin }
+func (c pipelineAppContext) Stdout() io.Writer { return io.Discard }
+func (c pipelineAppContext) IsPipeline() bool  { return c.isPipeline }
+
+// stdinAppContext doesn't report pipeline detection.
+type stdinAppContext struct {
+	AppContext[UnimplementedActionConfig]
+
+	stdin io.Reader
+}
+
+func (c stdinAppContext) Stdin() io.Reader  { return c.stdin }
+func (c stdinAppContext) Stdout() io.Writer { return io.Discard }
+
+func TestBufferedStdin(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name       string
+		isPipeline bool
+		size       int
+		wantSize   int
+	}{
+		{"pipeline", true, 64 * 1024, 64 * 1024},
+		{"pipeline default", true, 0, 4096},
+		{"interactive", false, 64 * 1024, 16},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := pipelineAppContext{stdin: strings.NewReader("line\n"), isPipeline: tt.isPipeline}
+
+			r, ok := BufferedStdin[UnimplementedActionConfig](ctx, tt.size)
+			if !ok {
+				t.Fatal("expected pipeline capability")
+			}
+
+			if r.Size() != tt.wantSize {
+				t.Errorf("expected buffer size %v, got %v", tt.wantSize, r.Size())
+			}
+
+			if line, err := r.ReadString('\n'); err != nil || line != "line\n" {
+				t.Errorf("unexpected read result %q, %v", line, err)
+			}
+		})
+	}
+
+	t.Run("no detection", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := stdinAppContext{stdin: strings.NewReader("")}
+
+		r, ok := BufferedStdin[UnimplementedActionConfig](ctx, 0)
+		if !ok {
+			t.Fatal("expected pipeline capability")
+		}
+
+		if r.Size() != 4096 {
+			t.Errorf("expected stdin to be considered piped, got buffer size %v", r.Size())
+		}
+	})
+
+	t.Run("no capability", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := struct {
+			AppContext[UnimplementedActionConfig]
+		}{}
+		if _, ok := BufferedStdin[UnimplementedActionConfig](ctx, 0); ok {
+			t.Error("expected no pipeline capability")
+		}
+	})
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("buffer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := pipelineAppContext{stdin: strings.NewReader("first\nsecond\r\n\nlast"), isPipeline: true}
+
+		lines, ok := Lines[UnimplementedActionConfig](t.Context(), ctx)
+		if !ok {
+			t.Fatal("expected pipeline capability")
+		}
+
+		var got []string
+		for line, err := range lines {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got = append(got, line)
+		}
+
+		if want := []string{"first", "second", "", "last"}; strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		t.Parallel()
+
+		errRead := errors.New("broken pipe")
+		ctx := pipelineAppContext{stdin: io.MultiReader(strings.NewReader("first\n"), iotest.ErrReader(errRead))}
+
+		lines, _ := Lines[UnimplementedActionConfig](t.Context(), ctx)
+
+		var (
+			got  []string
+			errs []error
+		)
+		for line, err := range lines {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			got = append(got, line)
+		}
+
+		if len(got) != 1 || got[0] != "first" {
+			t.Errorf("expected lines before the error, got %q", got)
+		}
+
+		if len(errs) != 1 || !errors.Is(errs[0], errRead) {
+			t.Errorf("expected single read error, got %v", errs)
+		}
+	})
+}
+
+func TestLineReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("buffer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := pipelineAppContext{stdin: strings.NewReader("first\nsecond\n"), isPipeline: true}
+
+		scanner, ok := LineReader[UnimplementedActionConfig](ctx)
+		if !ok {
+			t.Fatal("expected pipeline capability")
+		}
+
+		var got []string
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+
+		if err := scanner.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if want := []string{"first", "second"}; strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := pipelineAppContext{stdin: iotest.ErrReader(context.Canceled), isPipeline: true}
+
+		scanner, _ := LineReader[UnimplementedActionConfig](ctx)
+		if scanner.Scan() {
+			t.Errorf("expected no lines, got %q", scanner.Text())
+		}
+
+		if err := scanner.Err(); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("no capability", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := struct {
+			AppContext[UnimplementedActionConfig]
+		}{}
+		if _, ok := LineReader[UnimplementedActionConfig](ctx); ok {
+			t.Error("expected no pipeline capability")
+		}
+	})
+}
+
+func TestReadAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("buffer", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := pipelineAppContext{stdin: strings.NewReader("first\nlast"), isPipeline: true}
+
+		data, err := ReadAll[UnimplementedActionConfig](ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(data) != "first\nlast" {
+			t.Errorf("expected %q, got %q", "first\nlast", data)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := pipelineAppContext{stdin: io.MultiReader(strings.NewReader("first\n"), iotest.ErrReader(context.Canceled)), isPipeline: true}
+
+		data, err := ReadAll[UnimplementedActionConfig](ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+
+		if string(data) != "first\n" {
+			t.Errorf("expected data read before the error, got %q", data)
+		}
+	})
+
+	t.Run("no capability", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := struct {
+			AppContext[UnimplementedActionConfig]
+		}{}
+		if _, err := ReadAll[UnimplementedActionConfig](ctx); !errors.Is(err, ErrNoStdin) {
+			t.Errorf("expected %v, got %v", ErrNoStdin, err)
+		}
+	})
+}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"context"
+	"encoding"
+	"strings"
 )
 
 type ctxAppNameKey struct{}
@@ -72,3 +74,26 @@ func (v AppName) Name() (string, bool) { return v.name, v.name != "" }
 // Title returns the human-friendly application title with the same
 // explicit/implicit semantics as Name.
 func (v AppName) Title() (string, bool) { return v.title, v.title != "" }
+
+var (
+	_ encoding.TextMarshaler   = AppName{}
+	_ encoding.TextUnmarshaler = (*AppName)(nil)
+)
+
+// MarshalText implements [encoding.TextMarshaler], encoding name as
+// "name:title".
+func (v AppName) MarshalText() ([]byte, error) {
+	return []byte(v.name + ":" + v.title), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], so [AppName] can be
+// parsed from environment. Text is "name:title", where title may contain
+// colons, or just "name". Value is rebuilt with [NewAppName], so missing
+// components get defaults.
+func (v *AppName) UnmarshalText(text []byte) error {
+	name, title, _ := strings.Cut(string(text), ":")
+
+	*v = NewAppName(name, title)
+
+	return nil
+}
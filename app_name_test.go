@@ -0,0 +1,68 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/quenbyako/core"
+)
+
+func TestAppNameText(t *testing.T) {
+	t.Parallel()
+
+	for text, tt := range map[string]struct {
+		name, title string
+		marshaled   string
+	}{
+		"billing:Billing Service":  {name: "billing", title: "Billing Service", marshaled: "billing:Billing Service"},
+		"billing:Billing: Backend": {name: "billing", title: "Billing: Backend", marshaled: "billing:Billing: Backend"},
+		"billing":                  {name: "billing", title: DefaultAppTitle, marshaled: "billing:" + DefaultAppTitle},
+		":Billing Service":         {name: DefaultAppName, title: "Billing Service", marshaled: DefaultAppName + ":Billing Service"},
+		"":                         {name: DefaultAppName, title: DefaultAppTitle, marshaled: DefaultAppName + ":" + DefaultAppTitle},
+	} {
+		t.Run(text, func(t *testing.T) {
+			t.Parallel()
+
+			var v AppName
+			if err := v.UnmarshalText([]byte(text)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if name, _ := v.Name(); name != tt.name {
+				t.Errorf("expected name %q, got %q", tt.name, name)
+			}
+
+			if title, _ := v.Title(); title != tt.title {
+				t.Errorf("expected title %q, got %q", tt.title, title)
+			}
+
+			data, err := v.MarshalText()
+			if err != nil || string(data) != tt.marshaled {
+				t.Fatalf("expected %q, got %q, %v", tt.marshaled, data, err)
+			}
+
+			var roundTrip AppName
+			if err := roundTrip.UnmarshalText(data); err != nil || roundTrip != v {
+				t.Errorf("round trip mismatch: %v != %v, %v", roundTrip, v, err)
+			}
+		})
+	}
+
+	t.Run("env parser", func(t *testing.T) {
+		t.Parallel()
+
+		f, _, ok := GetParseFunc(reflect.TypeFor[AppName]())
+		if !ok {
+			t.Fatal("expected AppName parser")
+		}
+
+		v, err := f(t.Context(), "billing:Billing Service")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if v != NewAppName("billing", "Billing Service") {
+			t.Errorf("expected parsed name, got %v", v)
+		}
+	})
+}
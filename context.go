@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"sync"
+	"syscall"
 )
 
 // BuildContext constructs a root application context annotated with identity
@@ -12,8 +14,9 @@ import (
 // MUST be invoked by the caller to release signal resources.
 //
 // Cancellation Sources:
-//   - Incoming SIGINT / SIGKILL ([os.Interrupt], [os.Kill]) trigger context
-//     cancellation for graceful shutdown.
+//   - Incoming SIGINT / SIGTERM ([os.Interrupt], [syscall.SIGTERM]) trigger
+//     context cancellation for graceful shutdown. SIGTERM is what container
+//     runtimes and service managers send on stop.
 //   - Manual invocation of the returned cancel function.
 //
 // The supplied [Pipeline] is stored for later retrieval via [PipelinesFromContext].
@@ -27,10 +30,133 @@ func BuildContext(
 	ctx context.Context,
 	cancel context.CancelFunc,
 ) {
-	ctx, cancel = signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	return BuildContextWithOptions(name, version, pipeline)
+}
+
+// BuildContextWithSignals works like [BuildContext], but cancels the context
+// when any of sigs is received instead of the default set, e.g. to ignore
+// SIGINT in daemon mode. Other signals keep their default behavior. With no
+// signals, the context is cancelled only by the returned cancel function.
+func BuildContextWithSignals(
+	name AppName,
+	version AppVersion,
+	pipeline Pipeline,
+	sigs ...os.Signal,
+) (
+	ctx context.Context,
+	cancel context.CancelFunc,
+) {
+	return BuildContextWithOptions(name, version, pipeline, WithSignals(sigs...))
+}
+
+type buildParams struct {
+	signals   []os.Signal
+	forceQuit func()
+}
+
+// forceQuitExitCode is exit code of forced quit, following shell convention
+// for SIGINT (128 + 2).
+const forceQuitExitCode = 130
+
+type BuildOption func(*buildParams)
+
+// WithSignals sets signals cancelling the context built with
+// [BuildContextWithOptions], [os.Interrupt] and [syscall.SIGTERM] by default.
+// Note that [os.Kill] can't be caught, so it never cancels the context.
+func WithSignals(sigs ...os.Signal) BuildOption {
+	return func(p *buildParams) { p.signals = sigs }
+}
+
+// WithForceQuit makes the second signal (see [WithSignals]) call forceQuit,
+// so user can abort hanging graceful shutdown by pressing Ctrl+C twice. The
+// first signal still cancels the context. Nil forceQuit exits the process with
+// code 130.
+func WithForceQuit(forceQuit func()) BuildOption {
+	return func(p *buildParams) {
+		if forceQuit == nil {
+			forceQuit = func() { os.Exit(forceQuitExitCode) }
+		}
+
+		p.forceQuit = forceQuit
+	}
+}
+
+// BuildContextWithOptions is the functional-option form of [BuildContext].
+func BuildContextWithOptions(
+	name AppName,
+	version AppVersion,
+	pipeline Pipeline,
+	opts ...BuildOption,
+) (
+	ctx context.Context,
+	cancel context.CancelFunc,
+) {
+	p := buildParams{
+		signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+	for _, o := range opts {
+		o(&p)
+	}
+
+	// NotifyContext without signals relays all of them, which is the opposite
+	// of what caller asked for.
+	switch {
+	case len(p.signals) == 0:
+		ctx, cancel = context.WithCancel(context.Background())
+	case p.forceQuit != nil:
+		ctx, cancel = notifyForceQuitContext(p.signals, p.forceQuit)
+	default:
+		ctx, cancel = signal.NotifyContext(context.Background(), p.signals...)
+	}
+
 	ctx = WithAppName(ctx, name)
 	ctx = WithVersion(ctx, version)
 	ctx = WithPipelines(ctx, pipeline)
 
 	return ctx, cancel
 }
+
+// notifyForceQuitContext works like [signal.NotifyContext], but keeps
+// listening after the context is cancelled, calling forceQuit on the second
+// signal.
+func notifyForceQuitContext(sigs []os.Signal, forceQuit func()) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	done := make(chan struct{})
+	go watchSignals(ch, done, cancel, forceQuit)
+
+	var once sync.Once
+
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+		cancel()
+	}
+}
+
+// watchSignals cancels on the first signal of ch and calls forceQuit on the
+// second, until done is closed.
+func watchSignals(ch <-chan os.Signal, done <-chan struct{}, cancel context.CancelFunc, forceQuit func()) {
+	for received := 0; ; {
+		select {
+		case <-done:
+			return
+		case <-ch:
+			received++
+		}
+
+		switch received {
+		case 1:
+			cancel()
+		case 2:
+			forceQuit()
+
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package core_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/quenbyako/core"
+)
+
+func TestWatchSignals(t *testing.T) {
+	ch := make(chan os.Signal)
+	done := make(chan struct{})
+	ctx, cancel := context.WithCancel(t.Context())
+	forced := make(chan struct{})
+
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		WatchSignals(ch, done, cancel, func() { close(forced) })
+	}()
+
+	ch <- os.Interrupt
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by the first signal")
+	}
+
+	select {
+	case <-forced:
+		t.Fatal("first signal forced quit")
+	default:
+	}
+
+	ch <- os.Interrupt
+
+	select {
+	case <-forced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal didn't force quit")
+	}
+
+	<-exited
+
+	t.Run("stops on done", func(t *testing.T) {
+		ch, done := make(chan os.Signal), make(chan struct{})
+
+		exited := make(chan struct{})
+		go func() {
+			defer close(exited)
+			WatchSignals(ch, done, func() {}, func() { t.Error("unexpected forced quit") })
+		}()
+
+		ch <- os.Interrupt
+		close(done)
+
+		select {
+		case <-exited:
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher didn't stop")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+//go:build unix
+
+package core_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/quenbyako/core"
+)
+
+func TestBuildContextWithSignals(t *testing.T) {
+	// catching excluded signal, as its default action terminates the process.
+	excluded := make(chan os.Signal, 1)
+	signal.Notify(excluded, syscall.SIGUSR2)
+	t.Cleanup(func() { signal.Stop(excluded) })
+
+	ctx, cancel := BuildContextWithSignals(AppName{}, AppVersion{}, PipelineFromFiles(os.Stdin, os.Stdout, os.Stderr), syscall.SIGUSR1)
+	t.Cleanup(cancel)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-excluded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("excluded signal was not delivered")
+	}
+
+	if ctx.Err() != nil {
+		t.Fatal("context cancelled by excluded signal")
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by configured signal")
+	}
+}
+
+func TestBuildContextWithoutSignals(t *testing.T) {
+	ctx, cancel := BuildContextWithOptions(AppName{}, AppVersion{}, PipelineFromFiles(os.Stdin, os.Stdout, os.Stderr), WithSignals())
+
+	if ctx.Err() != nil {
+		t.Fatal("context must not be cancelled yet")
+	}
+
+	cancel()
+
+	if !errors.Is(context.Cause(ctx), context.Canceled) {
+		t.Errorf("expected context to be cancelled, got %v", context.Cause(ctx))
+	}
+}
+
+func TestBuildContextDefaultSignals(t *testing.T) {
+	ctx, cancel := BuildContext(AppName{}, AppVersion{}, PipelineFromFiles(os.Stdin, os.Stdout, os.Stderr))
+	t.Cleanup(cancel)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by SIGTERM")
+	}
+}
+
+func TestBuildContextForceQuit(t *testing.T) {
+	forced := make(chan struct{})
+
+	ctx, cancel := BuildContextWithOptions(AppName{}, AppVersion{}, PipelineFromFiles(os.Stdin, os.Stdout, os.Stderr),
+		WithSignals(syscall.SIGUSR1),
+		WithForceQuit(func() { close(forced) }),
+	)
+	t.Cleanup(cancel)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context was not cancelled by the first signal")
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-forced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal didn't force quit")
+	}
+}
@@ -2,11 +2,17 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"net"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"buf.build/go/protovalidate"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
@@ -17,40 +23,73 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/stats"
 )
 
 // Server combines service registration and serving lifecycle semantics for a
 // configured gRPC server. The Serve method blocks until the context is
-// cancelled or the server stops.
+// cancelled or the server stops. When used with the runtime, Serve is called by
+// the runtime itself (see [core.Servable]) once action calls [core.Ready] or
+// returns, so actions only register services before that.
 type Server interface {
 	grpc.ServiceRegistrar
 
 	Serve(ctx context.Context) error
 }
 
+// Defaults of the connection management. Some of them could be overridden with
+// query parameters of the server address.
+const (
+	// DefaultKeepaliveTime is the inactivity time after which server pings a
+	// client, "keepalive_time" query parameter.
+	DefaultKeepaliveTime = 5 * time.Minute
+	// DefaultKeepaliveTimeout is the time to wait for ping ack before closing
+	// the connection, "keepalive_timeout" query parameter.
+	DefaultKeepaliveTimeout = 20 * time.Second
+	// DefaultMaxConnectionAge is the age after which connection is gracefully
+	// closed, so clients re-resolve and rebalance, "max_conn_age" query
+	// parameter.
+	DefaultMaxConnectionAge = 30 * time.Minute
+	// DefaultMaxConnectionAgeGrace is the time given to in-flight RPCs after
+	// max connection age is reached.
+	DefaultMaxConnectionAgeGrace = 10 * time.Second
+	// DefaultKeepaliveMinTime is the minimal interval of client pings, more
+	// frequent pings are treated as abuse.
+	DefaultKeepaliveMinTime = 10 * time.Second
+)
+
 func init() {
 	core.RegisterEnvParser(parseGRPCServer)
 }
 
 type grpcServerWrapper struct {
-	log  *slog.Logger
-	addr net.Addr
+	log    *slog.Logger
+	addr   net.Addr
+	params serverParams
 
 	conn net.Listener
 	srv  *grpc.Server
+	// set by the first Serve call.
+	serving atomic.Bool
 }
 
 var _ core.EnvParam = (*grpcServerWrapper)(nil)
+var _ core.Servable = (*grpcServerWrapper)(nil)
 var _ Server = (*grpcServerWrapper)(nil)
 
 func parseGRPCServer(ctx context.Context, v string) (Server, error) {
+	if v == "" {
+		return nil, fmt.Errorf("gRPC server: %w", core.ErrAddrNotSet)
+	}
+
 	u, err := url.Parse(v)
 	if err != nil {
 		return nil, err
 	}
-	if u.Scheme != "grpc" {
+	if u.Scheme != "grpc" && u.Scheme != "grpcs" {
 		return nil, fmt.Errorf("unsupported gRPC scheme %q", u.Scheme)
 	}
 
@@ -72,13 +111,166 @@ func parseGRPCServer(ctx context.Context, v string) (Server, error) {
 	}
 	addr := &net.TCPAddr{IP: ip, Port: portNum}
 
+	params, err := parseServerParams(u.Query())
+	if err != nil {
+		return nil, err
+	}
+	params.tls = params.tls || u.Scheme == "grpcs"
+
 	return &grpcServerWrapper{
-		addr: addr,
+		addr:   addr,
+		params: params,
 	}, nil
 }
 
+// serverParams are optional server settings, passed as query parameters of
+// the server address. Zero values keep grpc-go defaults.
+type serverParams struct {
+	// grpcs scheme or tls=true: serve over TLS with application certificate.
+	tls bool
+	// maxrecv: max size of received message, e.g. "16mb".
+	maxRecv int
+	// maxsend: max size of sent message, e.g. "16mb".
+	maxSend int
+
+	keepaliveTime    time.Duration
+	keepaliveTimeout time.Duration
+	maxConnAge       time.Duration
+}
+
+func parseServerParams(query url.Values) (p serverParams, err error) {
+	if v := query.Get("tls"); v != "" {
+		if p.tls, err = strconv.ParseBool(v); err != nil {
+			return serverParams{}, fmt.Errorf("invalid tls: %w", err)
+		}
+	}
+
+	p.keepaliveTime = DefaultKeepaliveTime
+	p.keepaliveTimeout = DefaultKeepaliveTimeout
+	p.maxConnAge = DefaultMaxConnectionAge
+
+	for name, field := range map[string]*time.Duration{
+		"keepalive_time":    &p.keepaliveTime,
+		"keepalive_timeout": &p.keepaliveTimeout,
+		"max_conn_age":      &p.maxConnAge,
+	} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+
+		if *field, err = time.ParseDuration(v); err != nil {
+			return serverParams{}, fmt.Errorf("invalid %v: %w", name, err)
+		}
+
+		if *field <= 0 {
+			return serverParams{}, fmt.Errorf("invalid %v: must be positive, got %v", name, v)
+		}
+	}
+
+	for name, field := range map[string]*int{
+		"maxrecv": &p.maxRecv,
+		"maxsend": &p.maxSend,
+	} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+
+		if *field, err = parseByteSize(v); err != nil {
+			return serverParams{}, fmt.Errorf("invalid %v: %w", name, err)
+		}
+	}
+
+	return p, nil
+}
+
+func (p serverParams) keepalive() keepalive.ServerParameters {
+	return keepalive.ServerParameters{
+		MaxConnectionIdle:     0,
+		MaxConnectionAge:      p.maxConnAge,
+		MaxConnectionAgeGrace: DefaultMaxConnectionAgeGrace,
+		Time:                  p.keepaliveTime,
+		Timeout:               p.keepaliveTimeout,
+	}
+}
+
+func (p serverParams) options() []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(p.keepalive()),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             DefaultKeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+
+	if p.maxRecv > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(p.maxRecv))
+	}
+
+	if p.maxSend > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(p.maxSend))
+	}
+
+	return opts
+}
+
+//nolint:gochecknoglobals // constant table.
+var byteSizeSuffixes = []struct {
+	suffix string
+	mult   int64
+}{
+	// longest first, so "b" doesn't match "kb".
+	{"kb", 1 << 10},
+	{"mb", 1 << 20},
+	{"gb", 1 << 30},
+	{"b", 1},
+}
+
+// parseByteSize parses sizes like "512", "64kb" or "16MB". Suffixes are
+// binary: 1kb is 1024 bytes. Size must fit into int32, as message length in
+// gRPC framing does.
+func parseByteSize(v string) (int, error) {
+	num, mult := strings.ToLower(strings.TrimSpace(v)), int64(1)
+
+	for _, s := range byteSizeSuffixes {
+		if trimmed, ok := strings.CutSuffix(num, s.suffix); ok {
+			num, mult = strings.TrimSpace(trimmed), s.mult
+
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing size %q: %w", v, err)
+	}
+
+	if n <= 0 || n > math.MaxInt32/mult {
+		return 0, fmt.Errorf("size %q is out of range (0, %v]", v, math.MaxInt32)
+	}
+
+	return int(n * mult), nil
+}
+
 func (g *grpcServerWrapper) Configure(ctx context.Context, data *core.ConfigureData) error {
-	g.srv = newGRPCServer(data.Logger, data.Metric, data.Trace)
+	if g.addr == nil {
+		return fmt.Errorf("gRPC server: %w", core.ErrAddrNotSet)
+	}
+
+	var creds credentials.TransportCredentials
+	if g.params.tls {
+		if len(data.AppCert.Certificate) == 0 {
+			return errors.New("gRPC server over TLS requires application certificate")
+		}
+
+		creds = credentials.NewTLS(&tls.Config{ //nolint:exhaustruct // config has a lot of fields
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{data.AppCert},
+		})
+	}
+
+	g.srv = newGRPCServer(data.Logger, data.Metric, data.Trace, g.params, creds)
 	g.log = slog.New(data.Logger)
 
 	return nil
@@ -86,31 +278,55 @@ func (g *grpcServerWrapper) Configure(ctx context.Context, data *core.ConfigureD
 
 func (g *grpcServerWrapper) Acquire(ctx context.Context, data *core.AcquireData) error {
 	var err error
-	g.conn, err = net.Listen(g.addr.Network(), g.addr.String()) // TODO: handle error correctly
+	g.conn, err = net.Listen(g.addr.Network(), g.addr.String())
 	if err != nil {
-		return fmt.Errorf("listening on %q %q: %w", g.addr.Network(), g.addr.String(), err)
+		return core.ListenError(g.addr.Network(), g.addr.String(), err)
 	}
 
 	return nil
 }
 
+// String describes the server for logs: its URL with actual port, once the
+// address is acquired.
+func (g *grpcServerWrapper) String() string {
+	scheme := "grpc"
+	if g.params.tls {
+		scheme = "grpcs"
+	}
+
+	addr := g.addr
+	if g.conn != nil {
+		addr = g.conn.Addr()
+	}
+
+	return scheme + "://" + addr.String()
+}
+
 func (g *grpcServerWrapper) RegisterService(sd *grpc.ServiceDesc, ss any) {
 	g.srv.RegisterService(sd, ss)
 }
 
 func (g *grpcServerWrapper) Serve(ctx context.Context) error {
-	stopLocker := make(chan struct{})
-	go func() {
-		defer close(stopLocker)
+	// grpc-go doesn't allow serving twice, so if action serves by itself
+	// besides the runtime, the second call just waits for cancellation.
+	if !g.serving.CompareAndSwap(false, true) {
 		<-ctx.Done()
-		g.srv.GracefulStop()
-	}()
+
+		return nil
+	}
 
 	g.log.Info(
 		"starting gRPC server",
 		slog.String("addr", g.addr.String()),
 	)
 
+	stopLocker := make(chan struct{})
+	go func() {
+		defer close(stopLocker)
+		<-ctx.Done()
+		g.srv.GracefulStop()
+	}()
+
 	if err := g.srv.Serve(g.conn); err != nil {
 		return err //nolint:wrapcheck // no need to wrap
 	}
@@ -139,7 +355,14 @@ func (g *grpcServerWrapper) Shutdown(ctx context.Context, data *core.ShutdownDat
 	return nil
 }
 
-func newGRPCServer(logHandler slog.Handler, m metric.MeterProvider, t trace.TracerProvider) *grpc.Server {
+// newGRPCServer creates the server, serving plaintext if creds is nil.
+func newGRPCServer(
+	logHandler slog.Handler,
+	m metric.MeterProvider,
+	t trace.TracerProvider,
+	p serverParams,
+	creds credentials.TransportCredentials,
+) *grpc.Server {
 	v, err := protovalidate.New()
 	if err != nil {
 		panic(err)
@@ -164,6 +387,11 @@ func newGRPCServer(logHandler slog.Handler, m metric.MeterProvider, t trace.Trac
 		),
 		grpc.StatsHandler(grpcServerStats(m, t)),
 	}
+	opts = append(opts, p.options()...)
+
+	if creds != nil {
+		opts = append(opts, grpc.Creds(creds))
+	}
 
 	srv := grpc.NewServer(opts...)
 
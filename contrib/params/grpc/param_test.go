@@ -0,0 +1,443 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quenbyako/core"
+	noopMetric "go.opentelemetry.io/otel/metric/noop"
+	noopTrace "go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "512", want: 512},
+		{value: "512b", want: 512},
+		{value: "64kb", want: 64 << 10},
+		{value: "16mb", want: 16 << 20},
+		{value: "16MB", want: 16 << 20},
+		{value: "1gb", want: 1 << 30},
+		{value: "2gb", wantErr: true},
+		{value: "0", wantErr: true},
+		{value: "-1kb", wantErr: true},
+		{value: "mb", wantErr: true},
+		{value: "16tb", wantErr: true},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseServerParams(t *testing.T) {
+	srv, err := parseGRPCServer(t.Context(), "grpc://127.0.0.1:0?maxrecv=16mb&maxsend=1kb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	params := srv.(*grpcServerWrapper).params
+	if params.maxRecv != 16<<20 || params.maxSend != 1<<10 {
+		t.Errorf("unexpected params %+v", params)
+	}
+
+	// keepalive params and enforcement policy are always set.
+	if n := len(params.options()); n != 4 {
+		t.Errorf("expected 4 server options, got %v", n)
+	}
+
+	if n := len((serverParams{}).options()); n != 2 {
+		t.Errorf("expected only keepalive options by default, got %v", n)
+	}
+
+	if _, err := parseGRPCServer(t.Context(), "grpc://127.0.0.1:0?maxrecv=lots"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestKeepaliveParams(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p, err := parseServerParams(url.Values{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := keepalive.ServerParameters{
+			MaxConnectionAge:      DefaultMaxConnectionAge,
+			MaxConnectionAgeGrace: DefaultMaxConnectionAgeGrace,
+			Time:                  DefaultKeepaliveTime,
+			Timeout:               DefaultKeepaliveTimeout,
+		}
+		if got := p.keepalive(); got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		srv, err := parseGRPCServer(t.Context(), "grpc://127.0.0.1:0?keepalive_time=1m&keepalive_timeout=5s&max_conn_age=1h")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := keepalive.ServerParameters{
+			MaxConnectionAge:      time.Hour,
+			MaxConnectionAgeGrace: DefaultMaxConnectionAgeGrace,
+			Time:                  time.Minute,
+			Timeout:               5 * time.Second,
+		}
+		if got := srv.(*grpcServerWrapper).params.keepalive(); got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	for _, query := range []string{"keepalive_time=soon", "keepalive_timeout=0s", "max_conn_age=-1m"} {
+		t.Run(query, func(t *testing.T) {
+			if _, err := parseGRPCServer(t.Context(), "grpc://127.0.0.1:0?"+query); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// acquireServer configures and acquires server param, leaving serving to the
+// caller. Logger and otel providers of data are set by the helper.
+func acquireServer(t *testing.T, addr string, data *core.ConfigureData) *grpcServerWrapper {
+	t.Helper()
+
+	srv, err := parseGRPCServer(t.Context(), addr)
+	if err != nil {
+		t.Fatalf("parsing server: %v", err)
+	}
+
+	g, _ := srv.(*grpcServerWrapper)
+
+	data.Logger = slog.DiscardHandler
+	data.Metric = noopMetric.NewMeterProvider()
+	data.Trace = noopTrace.NewTracerProvider()
+
+	if err := g.Configure(t.Context(), data); err != nil {
+		t.Fatalf("configuring server: %v", err)
+	}
+
+	if err := g.Acquire(t.Context(), &core.AcquireData{}); err != nil {
+		t.Fatalf("acquiring server: %v", err)
+	}
+
+	t.Cleanup(func() { _ = g.Shutdown(t.Context(), &core.ShutdownData{}) })
+
+	return g
+}
+
+// serveServer serves g until the test ends.
+func serveServer(t *testing.T, g *grpcServerWrapper) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	served := make(chan error, 1)
+
+	go func() { served <- g.Serve(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-served; err != nil {
+			t.Errorf("serving: %v", err)
+		}
+	})
+}
+
+// startServer runs server param through the whole lifecycle, returning its
+// address.
+func startServer(t *testing.T, addr string, data *core.ConfigureData) string {
+	t.Helper()
+
+	g := acquireServer(t, addr, data)
+	serveServer(t, g)
+
+	return g.conn.Addr().String()
+}
+
+func TestMaxRecvMsgSize(t *testing.T) {
+	addr := startServer(t, "grpc://127.0.0.1:0?maxrecv=1kb", &core.ConfigureData{})
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(filename string) error {
+		if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: filename},
+		}); err != nil {
+			return err
+		}
+
+		_, err := stream.Recv()
+
+		return err
+	}
+
+	if err := send("small.proto"); err != nil {
+		t.Fatalf("small message must pass: %v", err)
+	}
+
+	if err := send(strings.Repeat("x", 2<<10)); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected %v, got %v", codes.ResourceExhausted, err)
+	}
+}
+
+func TestAcquireAddrInUse(t *testing.T) {
+	addr := startServer(t, "grpc://127.0.0.1:0", &core.ConfigureData{})
+
+	srv, err := parseGRPCServer(t.Context(), "grpc://"+addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = srv.(*grpcServerWrapper).Acquire(t.Context(), &core.AcquireData{})
+
+	var inUse *core.AddrInUseError
+	if !errors.As(err, &inUse) {
+		t.Fatalf("expected *core.AddrInUseError, got %v", err)
+	}
+
+	if inUse.Addr != addr {
+		t.Errorf("expected address %q, got %q", addr, inUse.Addr)
+	}
+}
+
+func TestAddrNotSet(t *testing.T) {
+	if _, err := parseGRPCServer(t.Context(), ""); !errors.Is(err, core.ErrAddrNotSet) {
+		t.Errorf("parsing: expected %v, got %v", core.ErrAddrNotSet, err)
+	}
+
+	// zero wrapper must fail on configuration instead of panicking on serve.
+	err := (&grpcServerWrapper{}).Configure(t.Context(), &core.ConfigureData{Logger: slog.DiscardHandler})
+	if !errors.Is(err, core.ErrAddrNotSet) {
+		t.Errorf("configuring: expected %v, got %v", core.ErrAddrNotSet, err)
+	}
+}
+
+func TestString(t *testing.T) {
+	for addr, want := range map[string]string{
+		"grpc://127.0.0.1:8080":          "grpc://127.0.0.1:8080",
+		"grpcs://127.0.0.1:8080":         "grpcs://127.0.0.1:8080",
+		"grpc://127.0.0.1:8080?tls=true": "grpcs://127.0.0.1:8080",
+	} {
+		srv, err := parseGRPCServer(t.Context(), addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := srv.(*grpcServerWrapper).String(); got != want {
+			t.Errorf("%v: expected %q, got %q", addr, want, got)
+		}
+	}
+}
+
+// selfSignedCert generates certificate for 127.0.0.1, returning it with the
+// pool trusting it.
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "grpc"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+// listServices calls reflection service, which is always registered.
+func listServices(t *testing.T, addr string, creds credentials.TransportCredentials) error {
+	t.Helper()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx, grpc.WaitForReady(false))
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		return err
+	}
+
+	_, err = stream.Recv()
+
+	return err
+}
+
+func TestServerTLS(t *testing.T) {
+	cert, pool := selfSignedCert(t)
+	tlsCreds := credentials.NewTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})
+
+	t.Run("plaintext", func(t *testing.T) {
+		addr := startServer(t, "grpc://127.0.0.1:0", &core.ConfigureData{})
+
+		if err := listServices(t, addr, insecure.NewCredentials()); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	for _, addr := range []string{"grpcs://127.0.0.1:0", "grpc://127.0.0.1:0?tls=true"} {
+		t.Run(addr, func(t *testing.T) {
+			addr := startServer(t, addr, &core.ConfigureData{AppCert: cert, Pool: pool})
+
+			if err := listServices(t, addr, tlsCreds); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := listServices(t, addr, insecure.NewCredentials()); err == nil {
+				t.Error("plaintext client must fail handshake")
+			}
+		})
+	}
+
+	t.Run("no certificate", func(t *testing.T) {
+		srv, err := parseGRPCServer(t.Context(), "grpcs://127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := srv.(*grpcServerWrapper).Configure(t.Context(), &core.ConfigureData{Logger: slog.DiscardHandler}); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestServeLateRegistration(t *testing.T) {
+	g := acquireServer(t, "grpc://127.0.0.1:0", &core.ConfigureData{})
+	addr := g.conn.Addr().String()
+
+	// early client connects once the address is bound, before action
+	// registers services.
+	early, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	// idle connection without handshake holds graceful stop until timeout.
+	defer early.Close()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	checked := make(chan error, 1)
+	go func() {
+		_, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		checked <- err
+	}()
+
+	select {
+	case err := <-checked:
+		t.Fatalf("request completed before serving: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	g.RegisterService(&healthpb.Health_ServiceDesc, health.NewServer())
+	serveServer(t, g)
+
+	if err := <-checked; err != nil {
+		t.Errorf("checking health: %v", err)
+	}
+}
+
+func TestServeTwice(t *testing.T) {
+	g := acquireServer(t, "grpc://127.0.0.1:0", &core.ConfigureData{})
+	g.RegisterService(&healthpb.Health_ServiceDesc, health.NewServer())
+	serveServer(t, g)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	served := make(chan error, 1)
+
+	go func() { served <- g.Serve(ctx) }()
+
+	if err := listServices(t, g.conn.Addr().String(), insecure.NewCredentials()); err != nil {
+		t.Errorf("listing services: %v", err)
+	}
+
+	cancel()
+
+	if err := <-served; err != nil {
+		t.Errorf("second serve: %v", err)
+	}
+}
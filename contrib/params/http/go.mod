@@ -4,11 +4,22 @@ go 1.25.1
 
 replace github.com/quenbyako/core => ../../..
 
-require github.com/quenbyako/core v0.0.0-00010101000000-000000000000
+require (
+	github.com/quenbyako/core v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.63.0
+	go.opentelemetry.io/otel/metric v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+	golang.org/x/net v0.43.0
+)
 
 require (
+	github.com/felixge/httpsnoop v1.0.4 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/open-feature/go-sdk v1.15.1 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/otel v1.38.0 // indirect
-	go.opentelemetry.io/otel/metric v1.38.0 // indirect
-	go.opentelemetry.io/otel/trace v1.38.0 // indirect
+	go.uber.org/mock v0.5.2 // indirect
 	golang.org/x/mod v0.29.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
 )
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/quenbyako/core"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func defaultMiddlewares(data *core.ConfigureData, p serverParams) []func(http.Handler) http.Handler {
+	var res []func(http.Handler) http.Handler
+
+	if p.otel {
+		res = append(res, Tracing(data.Metric, data.Trace))
+	}
+
+	if p.accessLog {
+		res = append(res, AccessLog(data.Logger))
+	}
+
+	return res
+}
+
+// Tracing traces and measures requests with otelhttp. Nil providers fall back
+// to the global ones.
+func Tracing(m metric.MeterProvider, t trace.TracerProvider) func(http.Handler) http.Handler {
+	var opts []otelhttp.Option
+	if m != nil {
+		opts = append(opts, otelhttp.WithMeterProvider(m))
+	}
+	if t != nil {
+		opts = append(opts, otelhttp.WithTracerProvider(t))
+	}
+
+	return otelhttp.NewMiddleware("http.server", opts...)
+}
+
+// AccessLog logs every served request with its status and duration.
+func AccessLog(h slog.Handler) func(http.Handler) http.Handler {
+	log := slog.New(h)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			log.InfoContext(r.Context(),
+				"served HTTP request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rw.status),
+				slog.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
+
+// statusRecorder remembers response status. Unwrap keeps
+// [http.ResponseController] working through it.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
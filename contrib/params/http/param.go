@@ -8,17 +8,28 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/quenbyako/core"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 // Server abstracts HTTP service registration and serving lifecycle. Register
 // installs a root handler; Serve blocks until context cancellation initiating
-// graceful shutdown.
+// graceful shutdown. When used with the runtime, Serve is called by the
+// runtime itself (see [core.Servable]), so actions only register a handler.
 type Server interface {
 	Register(http.Handler)
+	// Use adds middleware around the registered handler. Middlewares are
+	// applied in order: the first one added is the outermost. Default
+	// middlewares, [Tracing] and [AccessLog], wrap all of them, unless
+	// disabled with otel=false or access_log=false query parameters.
+	Use(middleware func(http.Handler) http.Handler)
 
 	Serve(ctx context.Context) error
 }
@@ -43,12 +54,75 @@ type httpServerWrapper struct {
 	conn net.Listener
 
 	srv *http.Server
+	// registered root handler. Resolved on each request, so a handler may be
+	// registered while the server is already serving.
+	handler atomic.Pointer[http.Handler]
+
+	params serverParams
+
+	middlewaresMu sync.Mutex
+	defaults      []func(http.Handler) http.Handler
+	middlewares   []func(http.Handler) http.Handler
+	// registered handler wrapped with middlewares. Rebuilt on each change for
+	// the same reason as handler.
+	chain atomic.Pointer[http.Handler]
+}
+
+// serverParams are optional server settings, passed as query parameters of
+// the server address.
+type serverParams struct {
+	// otel: trace and measure requests, true by default.
+	otel bool
+	// access_log: log every request, true by default.
+	accessLog bool
+	// h2c: serve HTTP/2 over cleartext along with HTTP/1.1, false by default.
+	h2c bool
+	// shutdown_timeout: time to wait for active requests on shutdown,
+	// [DefaultServerStopTimeout] by default.
+	shutdownTimeout time.Duration
+}
+
+func parseServerParams(query url.Values) (p serverParams, err error) {
+	p.otel, p.accessLog = true, true
+	p.shutdownTimeout = DefaultServerStopTimeout
+
+	if v := query.Get("shutdown_timeout"); v != "" {
+		if p.shutdownTimeout, err = time.ParseDuration(v); err != nil {
+			return serverParams{}, fmt.Errorf("invalid shutdown_timeout: %w", err)
+		}
+
+		if p.shutdownTimeout <= 0 {
+			return serverParams{}, fmt.Errorf("shutdown_timeout must be positive, got %v", v)
+		}
+	}
+
+	for name, field := range map[string]*bool{
+		"otel":       &p.otel,
+		"access_log": &p.accessLog,
+		"h2c":        &p.h2c,
+	} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+
+		if *field, err = strconv.ParseBool(v); err != nil {
+			return serverParams{}, fmt.Errorf("invalid %v: %w", name, err)
+		}
+	}
+
+	return p, nil
 }
 
 var _ core.EnvParam = (*httpServerWrapper)(nil)
+var _ core.Servable = (*httpServerWrapper)(nil)
 var _ Server = (*httpServerWrapper)(nil)
 
 func parseHTTPServer(ctx context.Context, v string) (Server, error) {
+	if v == "" {
+		return nil, fmt.Errorf("HTTP server: %w", core.ErrAddrNotSet)
+	}
+
 	u, err := url.Parse(v)
 	if err != nil {
 		return nil, err
@@ -76,36 +150,109 @@ func parseHTTPServer(ctx context.Context, v string) (Server, error) {
 	}
 	addr := &net.TCPAddr{IP: ip, Port: portNum}
 
-	return &httpServerWrapper{
-		addr: addr,
-		srv:  newHTTPServer(),
-	}, nil
+	params, err := parseServerParams(u.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	w := &httpServerWrapper{
+		addr:   addr,
+		srv:    newHTTPServer(),
+		params: params,
+	}
+	w.srv.Handler = http.HandlerFunc(w.serveHTTP)
+	if params.h2c {
+		w.srv.Handler = h2c.NewHandler(w.srv.Handler, &http2.Server{ //nolint:exhaustruct // defaults are fine
+			IdleTimeout: DefaultIdleTimeout,
+		})
+	}
+	w.rebuildChain()
+
+	return w, nil
 }
 
 func (g *httpServerWrapper) Configure(ctx context.Context, data *core.ConfigureData) error {
+	if g.addr == nil {
+		return fmt.Errorf("HTTP server: %w", core.ErrAddrNotSet)
+	}
+
 	g.log = slog.New(data.Logger)
 
+	g.middlewaresMu.Lock()
+	g.defaults = defaultMiddlewares(data, g.params)
+	g.middlewaresMu.Unlock()
+
+	g.rebuildChain()
+
 	return nil
 }
 
 func (g *httpServerWrapper) Acquire(ctx context.Context, data *core.AcquireData) error {
 	var err error
-	g.conn, err = net.Listen(g.addr.Network(), g.addr.String()) // TODO: handle error correctly
+	g.conn, err = net.Listen(g.addr.Network(), g.addr.String())
 	if err != nil {
-		return fmt.Errorf("listening on %q %q: %w", g.addr.Network(), g.addr.String(), err)
+		return core.ListenError(g.addr.Network(), g.addr.String(), err)
 	}
 
 	return nil
 }
 
+// String describes the server for logs: its URL with actual port, once the
+// address is acquired.
+func (h *httpServerWrapper) String() string {
+	addr := h.addr
+	if h.conn != nil {
+		addr = h.conn.Addr()
+	}
+
+	return "http://" + addr.String()
+}
+
 func (h *httpServerWrapper) Register(handler http.Handler) {
-	// NOTE(rcooper): makes no sense to make this thread-safe, because
-	// initialization usually performs in one goroutine.
-	if h.srv.Handler != nil {
+	// NOTE: registration is thread-safe, since the runtime serves params
+	// concurrently with the action, which registers handlers.
+	if !h.handler.CompareAndSwap(nil, &handler) {
 		panic("already registered")
 	}
+}
+
+func (h *httpServerWrapper) Use(middleware func(http.Handler) http.Handler) {
+	h.middlewaresMu.Lock()
+	h.middlewares = append(h.middlewares, middleware)
+	h.middlewaresMu.Unlock()
+
+	h.rebuildChain()
+}
+
+func (h *httpServerWrapper) rebuildChain() {
+	h.middlewaresMu.Lock()
+	defer h.middlewaresMu.Unlock()
+
+	var handler http.Handler = http.HandlerFunc(h.serveRegistered)
+	for _, m := range slices.Backward(h.middlewares) {
+		handler = m(handler)
+	}
+	for _, m := range slices.Backward(h.defaults) {
+		handler = m(handler)
+	}
+
+	h.chain.Store(&handler)
+}
+
+func (h *httpServerWrapper) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	(*h.chain.Load()).ServeHTTP(w, r)
+}
+
+// serveRegistered dispatches to the registered handler, responding with 404
+// until one is registered.
+func (h *httpServerWrapper) serveRegistered(w http.ResponseWriter, r *http.Request) {
+	if handler := h.handler.Load(); handler != nil {
+		(*handler).ServeHTTP(w, r)
+
+		return
+	}
 
-	h.srv.Handler = handler
+	http.NotFound(w, r)
 }
 
 func (h *httpServerWrapper) Serve(ctx context.Context) error {
@@ -113,17 +260,13 @@ func (h *httpServerWrapper) Serve(ctx context.Context) error {
 		panic("connection is not acquired")
 	}
 
-	if h.srv.Handler == nil {
-		h.srv.Handler = http.HandlerFunc(http.NotFound)
-	}
-
 	stopLocker := make(chan struct{})
 	var shutdownErr error
 	go func(err *error) {
 		defer close(stopLocker)
 		<-ctx.Done()
 
-		timeoutCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		timeoutCtx, cancel := context.WithTimeout(context.Background(), h.params.shutdownTimeout)
 		defer cancel()
 
 		*err = h.srv.Shutdown(timeoutCtx)
@@ -165,7 +308,7 @@ func (h *httpServerWrapper) Shutdown(ctx context.Context, data *core.ShutdownDat
 
 func newHTTPServer() *http.Server {
 	return &http.Server{ //nolint:exhaustruct // server has a lot of fields
-		// handler is 404 by default.
+		// handler is set by the wrapper.
 		Handler:           nil,
 		ReadTimeout:       DefaultReadTimeout,
 		ReadHeaderTimeout: DefaultReadHeaderTimeout,
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quenbyako/core"
+	"golang.org/x/net/http2"
+)
+
+func TestAcquireAddrInUse(t *testing.T) {
+	first, err := parseHTTPServer(t.Context(), "http://127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstWrapper, _ := first.(*httpServerWrapper)
+	if err := firstWrapper.Acquire(t.Context(), &core.AcquireData{}); err != nil {
+		t.Fatalf("acquiring server: %v", err)
+	}
+	t.Cleanup(func() { _ = firstWrapper.conn.Close() })
+
+	addr := firstWrapper.conn.Addr().String()
+
+	second, err := parseHTTPServer(t.Context(), "http://"+addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = second.(*httpServerWrapper).Acquire(t.Context(), &core.AcquireData{})
+
+	var inUse *core.AddrInUseError
+	if !errors.As(err, &inUse) {
+		t.Fatalf("expected *core.AddrInUseError, got %v", err)
+	}
+
+	if inUse.Addr != addr {
+		t.Errorf("expected address %q, got %q", addr, inUse.Addr)
+	}
+}
+
+func TestAddrNotSet(t *testing.T) {
+	if _, err := parseHTTPServer(t.Context(), ""); !errors.Is(err, core.ErrAddrNotSet) {
+		t.Errorf("parsing: expected %v, got %v", core.ErrAddrNotSet, err)
+	}
+
+	// zero wrapper must fail on configuration instead of panicking on serve.
+	err := (&httpServerWrapper{}).Configure(t.Context(), &core.ConfigureData{Logger: slog.DiscardHandler})
+	if !errors.Is(err, core.ErrAddrNotSet) {
+		t.Errorf("configuring: expected %v, got %v", core.ErrAddrNotSet, err)
+	}
+}
+
+// configured parses and configures server, without listening.
+func configured(t *testing.T, addr string, log slog.Handler) *httpServerWrapper {
+	t.Helper()
+
+	srv, err := parseHTTPServer(t.Context(), addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, _ := srv.(*httpServerWrapper)
+	if err := w.Configure(t.Context(), &core.ConfigureData{Logger: log}); err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+func TestMiddlewares(t *testing.T) {
+	var calls []string
+
+	trace := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	srv := configured(t, "http://127.0.0.1:0?otel=false&access_log=false", slog.DiscardHandler)
+	srv.Use(trace("first"))
+	srv.Register(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls = append(calls, "handler")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	// added after registration, must apply anyway.
+	srv.Use(trace("second"))
+
+	rec := httptest.NewRecorder()
+	srv.srv.Handler.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("expected status %v, got %v", http.StatusTeapot, rec.Code)
+	}
+
+	if got, want := strings.Join(calls, ","), "first,second,handler"; got != want {
+		t.Errorf("expected calls %q, got %q", want, got)
+	}
+}
+
+func TestDefaultMiddlewares(t *testing.T) {
+	var buf bytes.Buffer
+
+	srv := configured(t, "http://127.0.0.1:0", slog.NewJSONHandler(&buf, nil))
+	srv.Register(http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	srv.srv.Handler.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/missing", nil))
+
+	var entry struct {
+		Msg    string `json:"msg"`
+		Path   string `json:"path"`
+		Status int    `json:"status"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("parsing access log %q: %v", buf.String(), err)
+	}
+
+	if entry.Path != "/missing" || entry.Status != http.StatusNotFound {
+		t.Errorf("unexpected access log entry %+v", entry)
+	}
+
+	t.Run("opted out", func(t *testing.T) {
+		buf.Reset()
+
+		srv := configured(t, "http://127.0.0.1:0?access_log=false", slog.NewJSONHandler(&buf, nil))
+		srv.srv.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil))
+
+		if buf.Len() != 0 {
+			t.Errorf("expected no access log, got %q", buf.String())
+		}
+	})
+}
+
+// serving starts configured server, stopping it with the test.
+func serving(t *testing.T, addr string) *httpServerWrapper {
+	t.Helper()
+
+	srv := configured(t, addr, slog.DiscardHandler)
+	if err := srv.Acquire(t.Context(), &core.AcquireData{}); err != nil {
+		t.Fatalf("acquiring server: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-done; err != nil {
+			t.Errorf("serving: %v", err)
+		}
+	})
+
+	return srv
+}
+
+func TestH2C(t *testing.T) {
+	srv := serving(t, "http://127.0.0.1:0?h2c=true")
+	srv.Register(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	}))
+
+	// prior knowledge: HTTP/2 right away, without upgrade from HTTP/1.1.
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	t.Cleanup(client.CloseIdleConnections)
+
+	for name, tt := range map[string]struct {
+		client *http.Client
+		proto  string
+	}{
+		"http2":   {client: client, proto: "HTTP/2.0"},
+		"http1.1": {client: &http.Client{}, proto: "HTTP/1.1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+srv.conn.Addr().String(), nil)
+
+			resp, err := tt.client.Do(req)
+			if err != nil {
+				t.Fatalf("sending request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if resp.Proto != tt.proto || string(body) != tt.proto {
+				t.Errorf("expected %v, got response %v with body %q", tt.proto, resp.Proto, body)
+			}
+		})
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+
+	srv := configured(t, "http://127.0.0.1:0?shutdown_timeout="+timeout.String(), slog.DiscardHandler)
+	if err := srv.Acquire(t.Context(), &core.AcquireData{}); err != nil {
+		t.Fatalf("acquiring server: %v", err)
+	}
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	srv.Register(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx) }()
+
+	go func() { _, _ = http.Get("http://" + srv.conn.Addr().String()) }() //nolint:noctx // released at cleanup
+	<-entered
+
+	start := time.Now()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+
+		if elapsed := time.Since(start); elapsed < timeout {
+			t.Errorf("expected shutdown to wait for %v, took %v", timeout, elapsed)
+		}
+	case <-time.After(DefaultServerStopTimeout):
+		t.Fatal("shutdown timeout is not respected")
+	}
+}
+
+func TestParseServerParams(t *testing.T) {
+	for _, query := range []string{"otel=maybe", "shutdown_timeout=soon", "shutdown_timeout=0s"} {
+		if _, err := parseHTTPServer(t.Context(), "http://127.0.0.1:0?"+query); err == nil {
+			t.Errorf("%v: expected error", query)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	srv := configured(t, "http://127.0.0.1:0", slog.DiscardHandler)
+	if got := srv.String(); got != "http://127.0.0.1:0" {
+		t.Errorf("expected configured address, got %q", got)
+	}
+
+	if err := srv.Acquire(t.Context(), &core.AcquireData{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = srv.conn.Close() })
+
+	if want := "http://" + srv.conn.Addr().String(); srv.String() != want {
+		t.Errorf("expected acquired address %q, got %q", want, srv.String())
+	}
+}
@@ -8,10 +8,16 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"os"
+	"strconv"
 
 	"github.com/quenbyako/core"
 )
 
+// DefaultSocketMode is the permission of unix sockets, unless overridden with
+// "mode" query parameter.
+const DefaultSocketMode os.FileMode = 0o660
+
 // Listener is an alias to [net.Listener] exposed for semantic clarity.
 type Listener = net.Listener
 
@@ -46,6 +52,10 @@ func parseListener(ctx context.Context, v string) (Listener, error) {
 }
 
 func (l *netListenerWrapper) Configure(ctx context.Context, data *core.ConfigureData) error {
+	if l.network == nil || *l.network == (url.URL{}) {
+		return fmt.Errorf("listener: %w", core.ErrAddrNotSet)
+	}
+
 	l.config = &tls.Config{
 		MinVersion:                          tls.VersionTLS12,
 		Rand:                                nil,
@@ -96,10 +106,17 @@ func (l *netListenerWrapper) Acquire(ctx context.Context, data *core.AcquireData
 		},
 	}
 
-	l.Listener, err = listenConfig.Listen(ctx, l.network.Scheme, l.network.Host)
-	if err != nil {
-		// TODO: handle error
-		return fmt.Errorf("listening on %q %q: %w", l.network.Scheme, l.network.Host, err)
+	switch l.network.Scheme {
+	case "unix", "unixpacket":
+		l.Listener, err = listenUnix(ctx, listenConfig, l.network)
+		if err != nil {
+			return err
+		}
+	default:
+		l.Listener, err = listenConfig.Listen(ctx, l.network.Scheme, l.network.Host)
+		if err != nil {
+			return core.ListenError(l.network.Scheme, l.network.Host, err)
+		}
 	}
 
 	if l.config != nil {
@@ -109,6 +126,65 @@ func (l *netListenerWrapper) Acquire(ctx context.Context, data *core.AcquireData
 	return nil
 }
 
+// listenUnix listens on socket at uri path, e.g. unix:///run/app.sock. Stale
+// socket left by previous run is removed first. Socket permissions are set
+// from "mode" query parameter (octal), [DefaultSocketMode] by default.
+func listenUnix(ctx context.Context, listenConfig *net.ListenConfig, uri *url.URL) (net.Listener, error) {
+	path := uri.Path
+	if path == "" {
+		path = uri.Opaque
+	}
+
+	if path == "" {
+		return nil, fmt.Errorf("no socket path in %q", uri)
+	}
+
+	mode := DefaultSocketMode
+	if m := uri.Query().Get("mode"); m != "" {
+		parsed, err := strconv.ParseUint(m, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parsing socket mode %q: %w", m, err)
+		}
+
+		mode = os.FileMode(parsed)
+	}
+
+	// removing only sockets, so misconfigured path won't delete regular files,
+	// and only stale ones, so running instance keeps its socket.
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 && !socketAlive(ctx, uri.Scheme, path) {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket %q: %w", path, err)
+		}
+	}
+
+	listener, err := listenConfig.Listen(ctx, uri.Scheme, path)
+	if err != nil {
+		return nil, core.ListenError(uri.Scheme, path, err)
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		_ = listener.Close()
+
+		return nil, fmt.Errorf("setting socket %q permissions: %w", path, err)
+	}
+
+	return listener, nil
+}
+
+// socketAlive reports whether someone is listening on socket.
+func socketAlive(ctx context.Context, network, path string) bool {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, network, path)
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+
+	return true
+}
+
 func (l *netListenerWrapper) Shutdown(ctx context.Context, data *core.ShutdownData) error {
 	if err := l.Close(); err != nil {
 		return fmt.Errorf("closing connection: %w", err)
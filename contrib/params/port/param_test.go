@@ -0,0 +1,157 @@
+package port
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/quenbyako/core"
+)
+
+// acquire parses and acquires listener without configuring TLS.
+func acquire(t *testing.T, v string) Listener {
+	t.Helper()
+
+	l, err := parseListener(t.Context(), v)
+	if err != nil {
+		t.Fatalf("parsing listener: %v", err)
+	}
+
+	param, _ := l.(core.EnvParam)
+	if err := param.Acquire(t.Context(), &core.AcquireData{}); err != nil {
+		t.Fatalf("acquiring listener: %v", err)
+	}
+
+	t.Cleanup(func() { _ = param.Shutdown(t.Context(), &core.ShutdownData{}) })
+
+	return l
+}
+
+// dial connects to the listener and waits until connection is accepted.
+func dial(t *testing.T, l Listener) {
+	t.Helper()
+
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+		accepted <- err
+	}()
+
+	conn, err := net.Dial(l.Addr().Network(), l.Addr().String())
+	if err != nil {
+		t.Fatalf("dialing %v: %v", l.Addr(), err)
+	}
+	_ = conn.Close()
+
+	if err := <-accepted; err != nil {
+		t.Fatalf("accepting connection: %v", err)
+	}
+}
+
+func TestListenTCP(t *testing.T) {
+	dial(t, acquire(t, "tcp://127.0.0.1:0"))
+}
+
+func TestListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	t.Run("default mode", func(t *testing.T) {
+		l := acquire(t, "unix://"+path)
+		dial(t, l)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if mode := info.Mode().Perm(); mode != DefaultSocketMode {
+			t.Errorf("expected mode %v, got %v", DefaultSocketMode, mode)
+		}
+	})
+
+	t.Run("stale socket", func(t *testing.T) {
+		stale, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// keeping the file, like crashed process does.
+		stale.(*net.UnixListener).SetUnlinkOnClose(false)
+		_ = stale.Close()
+
+		l := acquire(t, "unix://"+path+"?mode=0600")
+		dial(t, l)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if mode := info.Mode().Perm(); mode != 0o600 {
+			t.Errorf("expected mode %v, got %v", os.FileMode(0o600), mode)
+		}
+	})
+
+	t.Run("regular file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "config")
+		if err := os.WriteFile(file, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		l, _ := parseListener(t.Context(), "unix://"+file)
+		if err := l.(core.EnvParam).Acquire(t.Context(), &core.AcquireData{}); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("regular file must not be removed: %v", err)
+		}
+	})
+}
+
+func TestListenAddrInUse(t *testing.T) {
+	for scheme, addr := range map[string]string{
+		"tcp":  "127.0.0.1:0",
+		"unix": filepath.Join(t.TempDir(), "app.sock"),
+	} {
+		t.Run(scheme, func(t *testing.T) {
+			first := acquire(t, scheme+"://"+addr)
+
+			l, err := parseListener(t.Context(), scheme+"://"+first.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = l.(core.EnvParam).Acquire(t.Context(), &core.AcquireData{})
+
+			var inUse *core.AddrInUseError
+			if !errors.As(err, &inUse) {
+				t.Fatalf("expected *core.AddrInUseError, got %v", err)
+			}
+
+			if !errors.Is(err, syscall.EADDRINUSE) {
+				t.Errorf("expected error to wrap EADDRINUSE, got %v", err)
+			}
+
+			// first listener must keep working.
+			dial(t, first)
+		})
+	}
+}
+
+func TestAddrNotSet(t *testing.T) {
+	l, err := parseListener(t.Context(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = l.(core.EnvParam).Configure(t.Context(), &core.ConfigureData{})
+	if !errors.Is(err, core.ErrAddrNotSet) {
+		t.Errorf("expected %v, got %v", core.ErrAddrNotSet, err)
+	}
+}
@@ -1,11 +1,12 @@
 package runtime
 
 import (
+	"context"
 	"crypto/x509"
 	"io"
 	"log/slog"
 
-	// "github.com/open-feature/go-sdk/openfeature"
+	"github.com/open-feature/go-sdk/openfeature"
 	"github.com/quenbyako/core"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
@@ -21,10 +22,19 @@ type appCtx[T any] struct {
 	log    slog.Handler
 	metric metric.MeterProvider
 	trace  trace.TracerProvider
-	// Features       openfeature.IClient
+	// nil if feature flags are disabled.
+	features       openfeature.IClient
 	caCertificates *x509.CertPool
+	// nil if metrics server is disabled.
+	metricServer *promhttpWrapper
+	// shared with readiness probe of the metrics server.
+	health *healthRegistry
+	// starts servers, see [core.Ready].
+	ready *readySignal
 
-	IsPipeline bool
+	isPipeline bool
+	// arguments passed to [Run], nil for [RunContext].
+	args []string
 }
 
 var _ _allTogether[core.UnimplementedActionConfig] = (*appCtx[core.UnimplementedActionConfig])(nil)
@@ -34,6 +44,11 @@ type _allTogether[T core.ActionConfig] interface {
 	core.LoggerAppContext[T]
 	core.ObservabilityAppContext[T]
 	core.PipelineAppContext[T]
+	core.FeatureAppContext[T]
+	core.ReadinessAppContext[T]
+	core.HealthAppContext[T]
+	core.ArgsAppContext[T]
+	core.ReadyAppContext[T]
 }
 
 func (a *appCtx[T]) Name() core.AppName       { return a.appName }
@@ -49,6 +64,42 @@ func (a *appCtx[T]) Observability() core.Metrics {
 }
 func (a *appCtx[T]) Stdin() io.Reader  { return a.stdin }
 func (a *appCtx[T]) Stdout() io.Writer { return a.stdout }
+func (a *appCtx[T]) IsPipeline() bool  { return a.isPipeline }
+func (a *appCtx[T]) Args() []string    { return a.args }
+
+// AddReadyCheck implements [core.ReadinessAppContext]. If metrics server is
+// disabled, there is no readiness probe, so check is ignored.
+func (a *appCtx[T]) AddReadyCheck(check func(context.Context) bool) {
+	a.metricServer.AddReadyCheck(check)
+}
+
+// RegisterHealthCheck implements [core.HealthAppContext]. If metrics server is
+// disabled, checks are kept, but never called.
+func (a *appCtx[T]) RegisterHealthCheck(name string, check func(context.Context) error) {
+	a.health.RegisterHealthCheck(name, check)
+}
+
+// Ready implements [core.ReadyAppContext].
+func (a *appCtx[T]) Ready() { a.ready.fire() }
+
+//nolint:ireturn // returns interface on intention.
+func (a *appCtx[T]) Features() openfeature.IClient { return a.features }
+
+// ctxReader checks ctx before each read, so stdin of the action stops once
+// action context is cancelled. It can't interrupt a read which is already
+// blocked, but stops reading between chunks.
+type ctxReader struct {
+	ctx context.Context //nolint:containedctx // checked on each read
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p) //nolint:wrapcheck // transparent wrapper
+}
 
 type appObservability struct {
 	metric.MeterProvider
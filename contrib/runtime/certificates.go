@@ -2,12 +2,19 @@ package runtime
 
 import (
 	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
+	"strings"
 )
 
-func loadCertificates(additionalPaths []string) *x509.CertPool {
+// loadCertificates adds CA certificates at additionalPaths of fsys to the
+// system pool. Paths may be glob patterns, see [fs.Glob]. Files are PEM
+// bundles (one or more certificates) or single DER certificates. Paths
+// without pattern characters must exist.
+func loadCertificates(fsys fs.FS, additionalPaths []string) (*x509.CertPool, error) {
 	certPool, err := x509.SystemCertPool()
 	if err != nil {
 		// On Windows, SystemCertPool() always returns nil, nil.
@@ -18,29 +25,88 @@ func loadCertificates(additionalPaths []string) *x509.CertPool {
 	}
 
 	for _, globPath := range additionalPaths {
-		// TODO: no os filesystem!!! only [fs.FS]!
-		paths, err := filepath.Glob(globPath)
+		paths, err := fs.Glob(fsys, globPath)
 		if err != nil {
-			// todo: for now panic, later return error
-			panic(fmt.Errorf("parsing glob %q: %w", globPath, err))
+			return nil, fmt.Errorf("parsing glob %q: %w", globPath, err)
+		}
+
+		// plain path is not a pattern matching nothing, it's a missing file.
+		if len(paths) == 0 && !strings.ContainsAny(globPath, `*?[`) {
+			paths = []string{globPath}
 		}
 
 		for _, path := range paths {
-			data, err := os.ReadFile(path)
+			data, err := fs.ReadFile(fsys, path)
 			if err != nil {
-				// todo: for now panic, later return error
-				panic(fmt.Errorf("reading CA certificate %q: %w", path, err))
+				return nil, fmt.Errorf("reading CA certificate %q: %w", path, err)
 			}
 
-			cert, err := x509.ParseCertificate(data)
+			certs, err := parseCertificates(data)
 			if err != nil {
-				// todo: for now panic, later return error
-				panic(fmt.Errorf("parsing CA certificate %q: %w", path, err))
+				return nil, fmt.Errorf("parsing CA certificate %q: %w", path, err)
 			}
 
-			certPool.AddCert(cert)
+			for _, cert := range certs {
+				certPool.AddCert(cert)
+			}
 		}
 	}
 
-	return certPool
+	return certPool, nil
+}
+
+// rootPaths converts OS paths into paths of os.DirFS("/"), resolving
+// relative ones against the working directory.
+func rootPaths(paths []string) ([]string, error) {
+	res := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving path %q: %w", path, err)
+		}
+
+		res = append(res, strings.TrimPrefix(filepath.ToSlash(abs), "/"))
+	}
+
+	return res, nil
+}
+
+// parseCertificates parses every certificate of PEM bundle, falling back to
+// a single DER certificate, if data is not PEM encoded.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var (
+		certs []*x509.Certificate
+		found bool
+	)
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		found = true
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate #%v: %w", len(certs)+1, err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if !found {
+		cert, err := x509.ParseCertificate(data)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // wrapped by caller
+		}
+
+		return []*x509.Certificate{cert}, nil
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates in PEM data")
+	}
+
+	return certs, nil
 }
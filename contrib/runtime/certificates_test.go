@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadCertificates(t *testing.T) {
+	first, _ := selfSignedCert(t)
+	second, _ := selfSignedCert(t)
+
+	var bundle []byte
+	for _, der := range [][]byte{first.Certificate[0], second.Certificate[0]} {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	fsys := fstest.MapFS{
+		"certs/bundle.pem":    {Data: bundle},
+		"certs/single.der":    {Data: first.Certificate[0]},
+		"extra/first.pem":     {Data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: first.Certificate[0]})},
+		"extra/second.pem":    {Data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: second.Certificate[0]})},
+		"invalid/invalid.pem": {Data: []byte("-----BEGIN CERTIFICATE-----\naGVsbG8=\n-----END CERTIFICATE-----\n")},
+		"invalid/key.pem":     {Data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})},
+	}
+
+	t.Run("pem bundle", func(t *testing.T) {
+		pool, err := loadCertificates(fsys, []string{"certs/bundle.pem"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, cert := range []*x509.Certificate{first.Leaf, second.Leaf} {
+			if _, err := cert.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+				t.Errorf("certificate from bundle is not trusted: %v", err)
+			}
+		}
+	})
+
+	t.Run("multiple files", func(t *testing.T) {
+		pool, err := loadCertificates(fsys, []string{"extra/*.pem"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, cert := range []*x509.Certificate{first.Leaf, second.Leaf} {
+			if _, err := cert.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+				t.Errorf("certificate from directory is not trusted: %v", err)
+			}
+		}
+	})
+
+	t.Run("der certificate", func(t *testing.T) {
+		pool, err := loadCertificates(fsys, []string{"certs/*.der"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := first.Leaf.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+			t.Errorf("certificate is not trusted: %v", err)
+		}
+	})
+
+	t.Run("pattern matching nothing", func(t *testing.T) {
+		if _, err := loadCertificates(fsys, []string{"certs/*.crt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadCertificates(fsys, []string{"certs/missing.pem"})
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected %v, got %v", fs.ErrNotExist, err)
+		}
+	})
+
+	for name, file := range map[string]string{
+		"invalid certificate": "invalid/invalid.pem",
+		"no certificates":     "invalid/key.pem",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadCertificates(fsys, []string{file})
+			if err == nil || !strings.Contains(err.Error(), "parsing CA certificate") {
+				t.Fatalf("expected parsing error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRootPaths(t *testing.T) {
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := rootPaths([]string{"/etc/ssl/certs/*.pem", "ca.pem"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"etc/ssl/certs/*.pem", strings.TrimPrefix(filepath.ToSlash(filepath.Join(wd, "ca.pem")), "/")}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Fatalf("expected %q, got %q", want, paths)
+	}
+
+	for _, path := range paths {
+		if !fs.ValidPath(path) {
+			t.Errorf("%q is not valid path of fs.FS", path)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+
+	"github.com/quenbyako/core"
+)
+
+// drainer tracks the drain phase: after the drain signal readiness probe
+// reports not-ready, and drainable params are asked to stop accepting new
+// work. Context is not cancelled, so the action continues running.
+type drainer struct {
+	log      LogCallbacks
+	params   []core.Drainable
+	draining atomic.Bool
+}
+
+// ready is a readiness probe, see [healthChecks].
+func (d *drainer) ready(context.Context) bool { return !d.draining.Load() }
+
+func (d *drainer) drain(ctx context.Context, sig os.Signal) {
+	if !d.draining.CompareAndSwap(false, true) {
+		return
+	}
+
+	d.log.DrainStarted(sig)
+
+	for _, p := range d.params {
+		if err := p.Drain(ctx); err != nil {
+			reportErrors(d.log, phaseError(PhaseDrain, p, fmt.Errorf("draining %T: %w", p, err)))
+		}
+	}
+}
+
+// watch starts draining once sig is received. Returned function stops
+// watching and waits until the watcher exits.
+func (d *drainer) watch(ctx context.Context, sig os.Signal) (stop func()) {
+	if sig == nil {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sig)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		select {
+		case <-ctx.Done():
+		case s := <-signals:
+			d.drain(ctx, s)
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		cancel()
+		wg.Wait()
+	}
+}
@@ -0,0 +1,118 @@
+//go:build unix
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quenbyako/core"
+	"github.com/quenbyako/core/internal/registrytest"
+)
+
+func TestDrainSignalReadiness(t *testing.T) {
+	d := &drainer{log: defaultLogs(slog.DiscardHandler)}
+	handler := healthChecks(prometheus.NewRegistry(), d.ready, nil, defaultProbePaths())
+
+	status := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		return rec.Code
+	}
+
+	stop := d.watch(t.Context(), syscall.SIGUSR1)
+	defer stop()
+
+	if got := status(); got != http.StatusOK {
+		t.Fatalf("expected ready before drain, got %v", got)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for status() != http.StatusServiceUnavailable {
+		if time.Now().After(deadline) {
+			t.Fatal("readiness did not flip after drain signal")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type failingDrain struct{}
+
+func (failingDrain) Drain(context.Context) error { return errors.New("listener is gone") }
+
+func TestDrainErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	next := &fakeParamData{drained: make(chan struct{})}
+	d := &drainer{
+		log:    defaultLogs(slog.NewJSONHandler(&buf, nil)),
+		params: []core.Drainable{failingDrain{}, next},
+	}
+
+	d.drain(t.Context(), syscall.SIGUSR1)
+
+	select {
+	case <-next.drained:
+	default:
+		t.Error("failed drain must not skip next params")
+	}
+
+	records := lifecycleErrors(t, buf.String())
+	if len(records) != 1 {
+		t.Fatalf("expected single lifecycle error, got %+v", records)
+	}
+
+	if r := records[0]; r.Phase != PhaseDrain || r.ParamType != "runtime.failingDrain" || !strings.Contains(r.Error, "listener is gone") {
+		t.Errorf("expected drain failure of the param, got %+v", r)
+	}
+}
+
+func TestRunDrainSignal(t *testing.T) {
+	registrytest.Reset(t)
+	registerFakeParam()
+
+	t.Setenv("RUNTIME_TEST_PARAM", "value")
+
+	code := Run(func(ctx context.Context, appCtx core.AppContext[fakeConfig]) core.ExitCode {
+		param := appCtx.Config().Param.data()
+
+		if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Error(err)
+
+			return 1
+		}
+
+		select {
+		case <-param.drained:
+		case <-time.After(5 * time.Second):
+			t.Error("param was not drained")
+
+			return 1
+		}
+
+		if err := ctx.Err(); err != nil {
+			t.Errorf("drain must not cancel the action: %v", err)
+		}
+
+		return 0
+	}, WithDrainSignal(syscall.SIGUSR1))(t.Context(), nil)
+	if code != 0 {
+		t.Fatalf("unexpected exit code %v", code)
+	}
+}
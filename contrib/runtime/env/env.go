@@ -13,6 +13,7 @@ const (
 	tagDefault     = "default"
 	tagSeparator   = "envSeparator"
 	tagKVSeparator = "envKeyValSeparator"
+	tagLayout      = "envLayout"
 )
 
 func slicePrefix(prefix string, index int) string {
@@ -9,6 +9,15 @@ import (
 var (
 	ErrNotStructPtr = errors.New("expected a pointer to a Struct")
 	ErrValueNotSet  = errors.New("required environment variable is not set")
+
+	// ErrAbortParse could be returned (or wrapped) by a parser to mark the whole
+	// config as unusable. Parsing stops immediately and only the field error
+	// containing it is returned, other fields are not processed.
+	ErrAbortParse = errors.New("parsing aborted")
+
+	// ErrInvalidPEM is returned for "pem" fields, which value is neither a raw
+	// PEM, nor base64 encoded one, nor a path to PEM file.
+	ErrInvalidPEM = errors.New("value is not a PEM, base64 encoded PEM or PEM file path")
 )
 
 type InvalidMapItemFormatError struct {
@@ -52,6 +61,8 @@ func (e *FieldError) Error() string {
 
 func (e *FieldError) Unwrap() error { return e.Err }
 
+func isAbort(err *FieldError) bool { return errors.Is(err, ErrAbortParse) }
+
 // NoParserError occurs when there is no parser provided for given type.
 type NoParserError struct {
 	Name string
@@ -1,10 +1,21 @@
 package env
 
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/quenbyako/core/secrets"
+)
+
 // parseParams for the parser.
 type parseParams struct {
 	environment map[string]string
 	prefix      string
 	onSet       func(tag string, value any, isDefault bool)
+	report      *Report
+	secrets     secrets.Engine
 }
 
 type Option func(*parseParams)
@@ -21,6 +32,12 @@ func WithOnSet(onSet func(tag string, value any, isDefault bool)) Option {
 	return func(p *parseParams) { p.onSet = onSet }
 }
 
+// WithSecrets sets engine resolving secret references in fields with "secret"
+// tag option.
+func WithSecrets(engine secrets.Engine) Option {
+	return func(p *parseParams) { p.secrets = engine }
+}
+
 func buildParseParams(opts ...Option) (parseParams, error) {
 	p := parseParams{
 		environment: nil,
@@ -44,7 +61,65 @@ func (p *parseParams) keyWithPrefix(key string) string {
 	return p.prefix + key
 }
 
+// getIndexedEnv collects KEY_0, KEY_1, ... values until the first missing
+// index. Returns false if there is no KEY_0.
+func (p *parseParams) getIndexedEnv(key string) ([]string, bool) {
+	var values []string
+
+	for i := 0; ; i++ {
+		val, ok := p.getEnv(key + "_" + strconv.Itoa(i))
+		if !ok {
+			return values, len(values) > 0
+		}
+
+		values = append(values, val)
+	}
+}
+
 func (p *parseParams) getEnv(key string) (string, bool) {
 	val, ok := p.environment[p.prefix+key]
 	return val, ok
 }
+
+const (
+	secretRefPrefix = "${secret:"
+	secretRefSuffix = "}"
+)
+
+// resolveSecrets replaces every ${secret:key} reference in value with the
+// secret stored under the key.
+func (p *parseParams) resolveSecrets(ctx context.Context, value string) (string, error) {
+	var res strings.Builder
+
+	for {
+		before, after, ok := strings.Cut(value, secretRefPrefix)
+		if !ok {
+			res.WriteString(value)
+
+			return res.String(), nil
+		}
+
+		key, rest, ok := strings.Cut(after, secretRefSuffix)
+		if !ok {
+			return "", fmt.Errorf("unterminated secret reference %q", secretRefPrefix+after)
+		}
+
+		if p.secrets == nil {
+			return "", fmt.Errorf("resolving secret %q: %w", key, secrets.ErrEngineNotConfigured)
+		}
+
+		secret, err := p.secrets.GetSecret(ctx, key)
+		if err != nil {
+			return "", fmt.Errorf("resolving secret %q: %w", key, err)
+		}
+
+		data, err := secret.Get(ctx)
+		if err != nil {
+			return "", fmt.Errorf("resolving secret %q: %w", key, err)
+		}
+
+		res.WriteString(before)
+		res.Write(data)
+		value = rest
+	}
+}
@@ -0,0 +1,49 @@
+package env
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"strings"
+)
+
+// loadPEM normalizes value of "pem" field to PEM bytes. Value is detected in
+// order: raw PEM, base64 encoded PEM, path to PEM file. filename is not empty,
+// if the value was read from file.
+func loadPEM(key, value string) (content []byte, filename string, err error) {
+	if isPEM([]byte(value)) {
+		return []byte(value), "", nil
+	}
+
+	// multi-line base64 blobs are common, e.g. from `base64 cert.pem`.
+	compact := strings.Join(strings.Fields(value), "")
+	if decoded, err := base64.StdEncoding.DecodeString(compact); err == nil && isPEM(decoded) {
+		return decoded, "", nil
+	}
+
+	content, err = os.ReadFile(value)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", ErrInvalidPEM
+		}
+
+		return nil, value, newLoadFileContentError(value, key, err)
+	}
+
+	if !isPEM(content) {
+		return nil, value, ErrInvalidPEM
+	}
+
+	return content, value, nil
+}
+
+func isPEM(data []byte) bool {
+	if !bytes.Contains(data, []byte("-----BEGIN ")) {
+		return false
+	}
+
+	block, _ := pem.Decode(data)
+
+	return block != nil
+}
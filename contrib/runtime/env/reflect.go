@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"unicode"
 
@@ -19,7 +21,13 @@ type fieldParams struct {
 	DefaultValue string
 	separator    string
 	kvSeparator  string
+	layout       string
 	defaultSet   bool
+	loadFile     bool
+	pem          bool
+	indexed      bool
+	secret       bool
+	sensitive    bool
 	ignored      bool
 }
 
@@ -129,6 +137,7 @@ func parseFieldParams(field reflect.StructField, prefix string) fieldParams {
 		DefaultValue: defaultValue,
 		separator:    separator,
 		kvSeparator:  kvSeparator,
+		layout:       field.Tag.Get(tagLayout),
 
 		ignored:    key == "-",
 		defaultSet: defaultSet,
@@ -138,6 +147,16 @@ func parseFieldParams(field reflect.StructField, prefix string) fieldParams {
 		switch tag {
 		case "":
 			continue
+		case "file":
+			result.loadFile = true
+		case "pem":
+			result.pem = true
+		case "indexed":
+			result.indexed = true
+		case "secret":
+			result.secret = true
+		case "sensitive":
+			result.sensitive = true
 		default:
 			panic(fmt.Sprintf("%q: unsupported tag option: %q", field.Name, tag))
 		}
@@ -146,21 +165,104 @@ func parseFieldParams(field reflect.StructField, prefix string) fieldParams {
 	return result
 }
 
+// parseContext returns the context passed to parser functions of this field.
+// It carries the field layout (if any), so it applies to scalar values as well
+// as to slice and map elements, without leaking into nested structs.
+func (f fieldParams) parseContext(ctx context.Context) context.Context {
+	if f.layout == "" {
+		return ctx
+	}
+
+	return core.WithParseLayout(ctx, f.layout)
+}
+
 func setValue(ctx context.Context, v reflect.Value, p parseParams, f fieldParams, prefix string) []*FieldError {
+	key := p.keyWithPrefix(f.key)
+
+	if f.indexed && v.Kind() == reflect.Slice {
+		if parts, ok := p.getIndexedEnv(f.key); ok {
+			errs := setSliceParts(f.parseContext(ctx), v, parts, f, p)
+
+			var err error
+			if len(errs) > 0 {
+				err = errs[0].Err
+			}
+			p.report.add(key, v.Type(), SourceEnv, "", err)
+
+			return errs
+		}
+	}
+
 	value, exists := p.getEnv(f.key)
-	var usingDefault bool
+	source := SourceEnv
 	if !exists || value == "" {
 		if !f.defaultSet {
+			p.report.add(key, v.Type(), SourceUnset, "", ErrValueNotSet)
+
 			return []*FieldError{
-				errField(p.keyWithPrefix(f.key), v.Type(), ErrValueNotSet),
+				errField(key, v.Type(), ErrValueNotSet),
 			}
 		}
 
 		value = f.DefaultValue
-		usingDefault = true
+		source = SourceDefault
+	}
+
+	// references are resolved in both env values and defaults, but only in
+	// the chosen one: env value, if set, takes precedence over default.
+	if f.secret {
+		resolved, err := p.resolveSecrets(ctx, value)
+		if err != nil {
+			p.report.add(key, v.Type(), source, "", err)
+
+			return []*FieldError{errField(key, v.Type(), err)}
+		}
+
+		value = resolved
+	}
+
+	var filename string
+	if f.loadFile && value != "" {
+		filename = value
+
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			err = newLoadFileContentError(filename, key, err)
+			p.report.add(key, v.Type(), source, filename, err)
+
+			return []*FieldError{errField(key, v.Type(), err)}
+		}
+
+		value = string(content)
+	}
+
+	if f.pem && value != "" {
+		content, pemFile, err := loadPEM(key, value)
+		if pemFile != "" {
+			filename = pemFile
+		}
+
+		if err != nil {
+			p.report.add(key, v.Type(), source, filename, err)
+
+			return []*FieldError{errField(key, v.Type(), err)}
+		}
+
+		// there is no parser for []byte, it would be split as a slice.
+		if v.Type() == reflect.TypeFor[[]byte]() {
+			v.SetBytes(content)
+			p.report.add(key, v.Type(), source, filename, nil)
+			if p.onSet != nil {
+				p.onSet(key, content, source == SourceDefault)
+			}
+
+			return nil
+		}
+
+		value = string(content)
 	}
 
-	if v.Kind() == reflect.Pointer {
+	if v.Kind() == reflect.Pointer && !ownParser(v.Type()) {
 		if v.Elem().Kind() == reflect.Invalid {
 			v.Set(reflect.New(v.Type().Elem()))
 		}
@@ -171,31 +273,54 @@ func setValue(ctx context.Context, v reflect.Value, p parseParams, f fieldParams
 	parserFunc, ptrDepth, ok := core.GetParseFunc(typ)
 	_ = ptrDepth // TODO: pointer restoration
 	if ok {
-		val, err := parserFunc(ctx, value)
+		val, err := parserFunc(f.parseContext(ctx), value)
 		if err != nil {
+			p.report.add(key, typ, source, filename, err)
+
 			return []*FieldError{
-				errField(p.keyWithPrefix(f.key), v.Type(), err),
+				errField(key, v.Type(), err),
 			}
 		}
-		value := reflect.ValueOf(val).Convert(typ)
+		// nil interface (e.g. fs.File of empty path) leaves field nil.
+		value := reflect.Zero(typ)
+		if val != nil {
+			value = reflect.ValueOf(val).Convert(typ)
+		}
 		v.Set(value)
+		p.report.add(key, typ, source, filename, nil)
 		if p.onSet != nil {
-			p.onSet(p.keyWithPrefix(f.key), value.Interface(), usingDefault)
+			p.onSet(key, value.Interface(), source == SourceDefault)
 		}
 
 		return nil
 	}
 
+	var errs []*FieldError
+
 	switch v.Kind() {
 	case reflect.Struct:
 		return setStruct(ctx, v, p, prefix)
 	case reflect.Slice:
-		return setSlice(ctx, v, value, f, p)
+		errs = setSlice(f.parseContext(ctx), v, value, f, p)
 	case reflect.Map:
-		return setMap(ctx, v, value, f, p)
+		errs = setMap(f.parseContext(ctx), v, value, f, p)
 	default:
 		panic(fmt.Sprintf("no parser found for %v, kind %v, env var %q", v.Type().String(), v.Kind(), f.key))
 	}
+
+	var err error
+	if len(errs) > 0 {
+		err = errs[0].Err
+	}
+	p.report.add(key, typ, source, filename, err)
+
+	return errs
+}
+
+// ownParser reports whether pointer type has parser of its own (e.g.
+// *os.File), so it's parsed as is, instead of parsing the pointed value.
+func ownParser(typ reflect.Type) bool {
+	return slices.Contains(core.RegisteredEnvParsers(), typ)
 }
 
 func setStruct(ctx context.Context, v reflect.Value, p parseParams, prefix string) (errs []*FieldError) {
@@ -206,6 +331,10 @@ func setStruct(ctx context.Context, v reflect.Value, p parseParams, prefix strin
 		refTypeField := refType.Field(i)
 
 		if err := setStructField(ctx, refField, refTypeField, p, structTagPrefix(prefix, refTypeField)); err != nil {
+			if i := slices.IndexFunc(err, isAbort); i >= 0 {
+				return err[i : i+1]
+			}
+
 			errs = append(errs, err...)
 		}
 	}
@@ -224,7 +353,7 @@ func setStructField(ctx context.Context, v reflect.Value, tags reflect.StructFie
 		return nil
 	}
 
-	if reflect.Ptr == v.Kind() && v.Elem().Kind() == reflect.Invalid {
+	if reflect.Ptr == v.Kind() && v.Elem().Kind() == reflect.Invalid && !ownParser(v.Type()) {
 		v.Set(reflect.New(v.Type().Elem()))
 		if v.Type().Elem().Kind() == reflect.Struct {
 			v = v.Elem()
@@ -243,6 +372,12 @@ func setSlice(ctx context.Context, field reflect.Value, value string, f fieldPar
 		panic("field is not a slice")
 	}
 
+	return setSliceParts(ctx, field, strings.Split(value, f.separator), f, p)
+}
+
+// setSliceParts parses already split slice items, e.g. from separated value or
+// from indexed variables.
+func setSliceParts(ctx context.Context, field reflect.Value, parts []string, f fieldParams, p parseParams) []*FieldError {
 	itemType := field.Type().Elem()
 	parserFunc, ptrDepth, ok := core.GetParseFunc(itemType)
 	if !ok {
@@ -250,14 +385,17 @@ func setSlice(ctx context.Context, field reflect.Value, value string, f fieldPar
 		panic(fmt.Sprintf("no parser found for %T", itemType))
 	}
 
-	parts := strings.Split(value, f.separator)
-
 	result := reflect.MakeSlice(field.Type(), len(parts), len(parts))
 	var errs []error
 	for i, part := range parts {
 		r, err := parserFunc(ctx, part)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("index %v: %w", i, err))
+			if errors.Is(err, ErrAbortParse) {
+				return []*FieldError{
+					errField(p.keyWithPrefix(f.key), field.Type(), errs[len(errs)-1]),
+				}
+			}
 		}
 		if len(errs) > 0 {
 			// no need to continue setting values if there are errors
@@ -324,13 +462,23 @@ func setMap(ctx context.Context, field reflect.Value, value string, f fieldParam
 
 		key, err := keyParserFunc(ctx, pairs[0])
 		if err != nil {
-			errs = append(errs, fmt.Errorf("key %q: %w", pairs[0], err))
+			err = fmt.Errorf("key %q: %w", pairs[0], err)
+			if errors.Is(err, ErrAbortParse) {
+				return []*FieldError{errField(p.keyWithPrefix(f.key), field.Type(), err)}
+			}
+
+			errs = append(errs, err)
 			continue
 		}
 
 		elem, err := elemParserFunc(ctx, pairs[1])
 		if err != nil {
-			errs = append(errs, fmt.Errorf("value %q: %w", pairs[1], err))
+			err = fmt.Errorf("value %q: %w", pairs[1], err)
+			if errors.Is(err, ErrAbortParse) {
+				return []*FieldError{errField(p.keyWithPrefix(f.key), field.Type(), err)}
+			}
+
+			errs = append(errs, err)
 			continue
 		}
 
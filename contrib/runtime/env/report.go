@@ -0,0 +1,97 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Source describes where the final value of a field came from.
+type Source uint8
+
+const (
+	// SourceUnset means that neither environment nor default provided a value.
+	SourceUnset Source = iota
+	// SourceEnv means that the value was taken from the environment.
+	SourceEnv
+	// SourceDefault means that the value was taken from the `default` tag.
+	SourceDefault
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceUnset:
+		return "unset"
+	case SourceEnv:
+		return "env"
+	case SourceDefault:
+		return "default"
+	default:
+		return fmt.Sprintf("Source(%d)", s)
+	}
+}
+
+// FieldReport describes how a single field was populated.
+type FieldReport struct {
+	// Final environment key, including all prefixes.
+	Key  string
+	Type reflect.Type
+	// Origin of the raw value. For fields tagged with `file`, it is the origin
+	// of the file path.
+	Source Source
+	// Path of the file the value was loaded from, if the field is tagged with
+	// `file`.
+	File string
+	// Parsing error of this field, if any.
+	Err error
+}
+
+// Default reports whether the default value was used.
+func (f FieldReport) Default() bool { return f.Source == SourceDefault }
+
+// Report is a structured representation of a single [ParseReport] call. Fields
+// are listed in the order they were visited.
+type Report struct {
+	Fields []FieldReport
+}
+
+// Field returns the report of a field by its final environment key.
+func (r Report) Field(key string) (FieldReport, bool) {
+	for _, f := range r.Fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+
+	return FieldReport{}, false
+}
+
+func (r *Report) add(key string, typ reflect.Type, source Source, file string, err error) {
+	if r == nil {
+		return
+	}
+
+	r.Fields = append(r.Fields, FieldReport{
+		Key:    key,
+		Type:   typ,
+		Source: source,
+		File:   file,
+		Err:    err,
+	})
+}
+
+// ParseReport works like [Parse], but additionally returns a report about
+// every visited field. The report is returned even if parsing fails, so it
+// includes failed fields as well.
+func ParseReport(ctx context.Context, v any, opts ...Option) (Report, error) {
+	p, err := buildParseParams(opts...)
+	if err != nil {
+		return Report{}, fmt.Errorf("options: %w", err)
+	}
+
+	p.report = &Report{}
+
+	err = parseInternal(ctx, v, p, "")
+
+	return *p.report, err
+}
@@ -0,0 +1,156 @@
+package env
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+
+	"github.com/quenbyako/core"
+)
+
+// Redacted replaces values of sensitive fields in [Snapshot].
+const Redacted = "[REDACTED]"
+
+// Snapshot returns the parsed values of config v (pointer to a struct already
+// populated by [Parse]) keyed by their final environment keys, the same as in
+// [Report]. Unlike raw environment, it reflects actual values, including
+// defaults.
+//
+// Values of fields tagged with `secret`, `file`, `pem` or `sensitive` options
+// (or nested in such struct) are replaced with [Redacted]. Values implementing
+// [encoding.TextMarshaler] or [fmt.Stringer] are converted to strings, so the
+// snapshot could be safely encoded as JSON, which sorts keys, keeping output
+// stable. URLs are converted with [url.URL.Redacted], hiding passwords of
+// DSNs.
+func Snapshot(v any, opts ...Option) (map[string]any, error) {
+	p, err := buildParseParams(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("options: %w", err)
+	}
+
+	ref := reflect.ValueOf(v)
+	if ref.Kind() != reflect.Pointer || ref.Elem().Kind() != reflect.Struct {
+		return nil, ErrNotStructPtr
+	}
+
+	res := make(map[string]any)
+	snapshotStruct(res, ref.Elem(), p, "", false)
+
+	return res, nil
+}
+
+func snapshotStruct(res map[string]any, v reflect.Value, p parseParams, prefix string, sensitive bool) {
+	refType := v.Type()
+
+	for i := range refType.NumField() {
+		field, typeField := v.Field(i), refType.Field(i)
+		if !field.CanInterface() {
+			continue
+		}
+
+		f := parseFieldParams(typeField, prefix)
+		if f.ignored {
+			continue
+		}
+
+		sensitive := sensitive || f.sensitive || f.secret || f.loadFile || f.pem
+
+		// nested structs without parser are flattened, exactly like parser
+		// does.
+		inner := field
+		for inner.Kind() == reflect.Pointer && !inner.IsNil() {
+			inner = inner.Elem()
+		}
+		if _, _, ok := core.GetParseFunc(inner.Type()); !ok && inner.Kind() == reflect.Struct {
+			snapshotStruct(res, inner, p, structTagPrefix(prefix, typeField), sensitive)
+
+			continue
+		}
+
+		key := p.keyWithPrefix(f.key)
+		if sensitive {
+			res[key] = Redacted
+		} else {
+			res[key] = snapshotValue(field)
+		}
+	}
+}
+
+func snapshotValue(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		if s, ok := stringValue(v); ok {
+			return s
+		}
+
+		v = v.Elem()
+	}
+
+	if s, ok := stringValue(v); ok {
+		return s
+	}
+
+	//nolint:exhaustive // other kinds are returned as is.
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		res := make([]any, v.Len())
+		for i := range v.Len() {
+			res[i] = snapshotValue(v.Index(i))
+		}
+
+		return res
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		res := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			res[fmt.Sprint(snapshotValue(iter.Key()))] = snapshotValue(iter.Value())
+		}
+
+		return res
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.Type().String()
+
+	default:
+		return v.Interface()
+	}
+}
+
+// stringValue converts text marshalers and stringers, looking up pointer
+// methods as well, if value is addressable.
+func stringValue(v reflect.Value) (string, bool) {
+	candidates := []reflect.Value{v}
+	if v.CanAddr() {
+		candidates = append(candidates, v.Addr())
+	}
+
+	for _, c := range candidates {
+		switch i := c.Interface().(type) {
+		// DSNs often carry credentials, String() keeps them.
+		case url.URL:
+			return i.Redacted(), true
+		case *url.URL:
+			return i.Redacted(), true
+		case encoding.TextMarshaler:
+			if text, err := i.MarshalText(); err == nil {
+				return string(text), true
+			}
+		case fmt.Stringer:
+			return i.String(), true
+		}
+	}
+
+	return "", false
+}
@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -171,6 +172,27 @@ type Options struct {
 	// Custom parse functions for different types.
 	FuncMap map[reflect.Type]ParserFunc
 
+	// FuncMapWithLayout returns parse functions for fields with "envLayout"
+	// tag, aware of the layout. They take precedence over FuncMap and
+	// encoding.TextUnmarshaler, e.g. for time.Time. If nil, layout is ignored.
+	FuncMapWithLayout func(layout string) map[reflect.Type]ParserFunc
+
+	// IsAbort reports whether error of a field marks the whole struct as
+	// unusable: parsing stops immediately, returning only this error.
+	IsAbort func(err error) bool
+
+	// GetSecret returns secret stored under the key, resolving ${secret:key}
+	// references in values of fields with "secret" tag option.
+	GetSecret func(key string) ([]byte, error)
+
+	// SkipSecrets leaves fields with "secret" tag option untouched, so they
+	// could be parsed later with ParseSecretsWithOptions, e.g. once secret
+	// storage is available.
+	SkipSecrets bool
+
+	// Used internally. processes only fields with "secret" tag option.
+	secretsOnly bool
+
 	// Used internally. maps the env variable key to its resolved string value.
 	// (for env var expansion)
 	rawEnvVars map[string]string
@@ -250,6 +272,11 @@ func optionsWithSliceEnvPrefix(opts Options, index int) Options {
 		UseFieldNameByDefault:        opts.UseFieldNameByDefault,
 		SetDefaultsForZeroValuesOnly: opts.SetDefaultsForZeroValuesOnly,
 		FuncMap:                      opts.FuncMap,
+		FuncMapWithLayout:            opts.FuncMapWithLayout,
+		IsAbort:                      opts.IsAbort,
+		GetSecret:                    opts.GetSecret,
+		SkipSecrets:                  opts.SkipSecrets,
+		secretsOnly:                  opts.secretsOnly,
 		rawEnvVars:                   opts.rawEnvVars,
 	}
 }
@@ -266,6 +293,11 @@ func optionsWithEnvPrefix(field reflect.StructField, opts Options) Options {
 		UseFieldNameByDefault:        opts.UseFieldNameByDefault,
 		SetDefaultsForZeroValuesOnly: opts.SetDefaultsForZeroValuesOnly,
 		FuncMap:                      opts.FuncMap,
+		FuncMapWithLayout:            opts.FuncMapWithLayout,
+		IsAbort:                      opts.IsAbort,
+		GetSecret:                    opts.GetSecret,
+		SkipSecrets:                  opts.SkipSecrets,
+		secretsOnly:                  opts.secretsOnly,
 		rawEnvVars:                   opts.rawEnvVars,
 	}
 }
@@ -282,6 +314,15 @@ func ParseWithOptions(v any, opts Options) error {
 	return parseInternal(v, setField, customOptions(opts))
 }
 
+// ParseSecretsWithOptions parses only fields with "secret" tag option,
+// skipped by ParseWithOptions with Options.SkipSecrets set.
+func ParseSecretsWithOptions(v any, opts Options) error {
+	opts = customOptions(opts)
+	opts.SkipSecrets, opts.secretsOnly = false, true
+
+	return parseInternal(v, setField, opts)
+}
+
 // ParseAs parses the given struct type containing `env` tags and loads its
 // values from environment variables.
 func ParseAs[T any]() (T, error) {
@@ -356,6 +397,13 @@ func doParse(ref reflect.Value, processField processFieldFn, opts Options) error
 		refTypeField := refType.Field(i)
 
 		if err := doParseField(refField, refTypeField, processField, opts); err != nil {
+			if aborts(err, opts) {
+				if val, ok := err.(AggregateError); ok {
+					return val
+				}
+				return newAggregateError(err)
+			}
+
 			if val, ok := err.(AggregateError); ok {
 				agrErr.Errors = append(agrErr.Errors, val.Errors...)
 			} else {
@@ -371,6 +419,18 @@ func doParse(ref reflect.Value, processField processFieldFn, opts Options) error
 	return agrErr
 }
 
+// aborts reports whether err of a field stops parsing, see Options.IsAbort.
+// Aggregated errors of nested structs are checked one by one.
+func aborts(err error, opts Options) bool {
+	if opts.IsAbort == nil {
+		return false
+	}
+	if val, ok := err.(AggregateError); ok {
+		return slices.ContainsFunc(val.Errors, opts.IsAbort)
+	}
+	return opts.IsAbort(err)
+}
+
 func doParseField(
 	refField reflect.Value,
 	refTypeField reflect.StructField,
@@ -380,7 +440,8 @@ func doParseField(
 	if !refField.CanSet() {
 		return nil
 	}
-	if refField.Kind() == reflect.Ptr && refField.Elem().Kind() == reflect.Struct && !refField.IsNil() {
+	_, ownParser := opts.FuncMap[refField.Type()]
+	if refField.Kind() == reflect.Ptr && refField.Elem().Kind() == reflect.Struct && !refField.IsNil() && !ownParser {
 		return parseInternal(refField.Interface(), processField, optionsWithEnvPrefix(refTypeField, opts))
 	}
 	if refField.Kind() == reflect.Struct && refField.CanAddr() && refField.Type().Name() == "" {
@@ -500,13 +561,42 @@ func doParseSlice(ref reflect.Value, processField processFieldFn, opts Options)
 }
 
 func setField(refField reflect.Value, refTypeField reflect.StructField, opts Options, fieldParams FieldParams) error {
+	if (fieldParams.Secret && opts.SkipSecrets) || (!fieldParams.Secret && opts.secretsOnly) {
+		return nil
+	}
+
+	funcMap, layout := opts.FuncMap, false
+	if fieldParams.Layout != "" && opts.FuncMapWithLayout != nil {
+		funcMap, layout = make(map[reflect.Type]ParserFunc, len(opts.FuncMap)), true
+		for k, v := range opts.FuncMap {
+			funcMap[k] = v
+		}
+		for k, v := range opts.FuncMapWithLayout(fieldParams.Layout) {
+			funcMap[k] = v
+		}
+	}
+
+	if fieldParams.Indexed && refField.Kind() == reflect.Slice {
+		if parts, ok := getIndexed(fieldParams.Key, opts.Environment); ok {
+			return handleSliceParts(refField, fieldParams.Key, parts, false, refTypeField, funcMap, layout, opts.OnSet)
+		}
+	}
+
 	value, isDefault, err := get(fieldParams, opts)
 	if err != nil {
 		return err
 	}
 
 	if value != "" && (!opts.SetDefaultsForZeroValuesOnly || refField.IsZero()) {
-		return set(refField, refTypeField, fieldParams.Key, value, isDefault, opts.FuncMap, opts.OnSet)
+		// []byte is a slice of numbers for other fields.
+		if fieldParams.PEM && refField.Type() == reflect.TypeFor[[]byte]() {
+			refField.SetBytes([]byte(value))
+			opts.OnSet(fieldParams.Key, refField.Interface(), isDefault)
+
+			return nil
+		}
+
+		return set(refField, refTypeField, fieldParams.Key, value, isDefault, funcMap, layout, opts.OnSet)
 	}
 
 	return nil
@@ -546,6 +636,11 @@ type FieldParams struct {
 	Expand          bool
 	Init            bool
 	Ignored         bool
+	PEM             bool
+	Secret          bool
+	Indexed         bool
+	Sensitive       bool
+	Layout          string
 }
 
 func parseFieldParams(field reflect.StructField, opts Options) (FieldParams, error) {
@@ -563,6 +658,7 @@ func parseFieldParams(field reflect.StructField, opts Options) (FieldParams, err
 		DefaultValue:    defaultValue,
 		HasDefaultValue: hasDefaultValue,
 		Ignored:         ownKey == "-",
+		Layout:          field.Tag.Get("envLayout"),
 	}
 
 	for _, tag := range tags {
@@ -581,6 +677,14 @@ func parseFieldParams(field reflect.StructField, opts Options) (FieldParams, err
 			result.Expand = true
 		case "init":
 			result.Init = true
+		case "pem":
+			result.PEM = true
+		case "secret":
+			result.Secret = true
+		case "indexed":
+			result.Indexed = true
+		case "sensitive":
+			result.Sensitive = true
 		case "-":
 			result.Ignored = true
 		default:
@@ -601,6 +705,14 @@ func get(fieldParams FieldParams, opts Options) (val string, isDefault bool, err
 		opts.Environment,
 	)
 
+	// resolved before expansion, which would treat references as variables.
+	if fieldParams.Secret && val != "" {
+		val, err = resolveSecrets(val, opts.GetSecret)
+		if err != nil {
+			return "", false, newResolveSecretError(fieldParams.Key, err)
+		}
+	}
+
 	if fieldParams.Expand {
 		val = os.Expand(val, opts.getRawEnv)
 	}
@@ -627,6 +739,13 @@ func get(fieldParams FieldParams, opts Options) (val string, isDefault bool, err
 		}
 	}
 
+	if fieldParams.PEM && val != "" {
+		val, err = getPEM(fieldParams.Key, val)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
 	return val, isDefault, err
 }
 
@@ -636,6 +755,21 @@ func parseKeyForOption(key string) (string, []string) {
 	return opts[0], opts[1:]
 }
 
+// getIndexed collects KEY_0, KEY_1, ... values until the first missing index.
+// Returns false if there is no KEY_0.
+func getIndexed(key string, envs map[string]string) ([]string, bool) {
+	var values []string
+
+	for i := 0; ; i++ {
+		val, ok := envs[key+"_"+strconv.Itoa(i)]
+		if !ok {
+			return values, len(values) > 0
+		}
+
+		values = append(values, val)
+	}
+}
+
 func getFromFile(filename string) (value string, err error) {
 	b, err := os.ReadFile(filename)
 	return string(b), err
@@ -655,8 +789,10 @@ func getOr(key, defaultValue string, defExists bool, envs map[string]string) (va
 	return value, true, false
 }
 
-func set(field reflect.Value, sf reflect.StructField, key, value string, isDefault bool, funcMap map[reflect.Type]ParserFunc, onSet OnSetFn) error {
-	if tm := asTextUnmarshaler(field); tm != nil {
+// set parses value into field. If layout is set, funcMap holds parsers aware of
+// field layout, taking precedence over encoding.TextUnmarshaler.
+func set(field reflect.Value, sf reflect.StructField, key, value string, isDefault bool, funcMap map[reflect.Type]ParserFunc, layout bool, onSet OnSetFn) error {
+	if tm := asTextUnmarshaler(field); tm != nil && !(layout && hasParser(funcMap, sf.Type)) {
 		if err := tm.UnmarshalText([]byte(value)); err != nil {
 			return newParseError(sf, err)
 		}
@@ -664,6 +800,19 @@ func set(field reflect.Value, sf reflect.StructField, key, value string, isDefau
 		return nil
 	}
 
+	// pointers with own parser (e.g. *os.File) are set as is.
+	if parserFunc, ok := funcMap[sf.Type]; ok && sf.Type.Kind() == reflect.Ptr {
+		val, err := parserFunc(value)
+		if err != nil {
+			return newParseError(sf, err)
+		}
+
+		field.Set(reflect.ValueOf(val))
+		onSet(key, field.Interface(), isDefault)
+
+		return nil
+	}
+
 	typee := sf.Type
 	fieldee := field
 	if typee.Kind() == reflect.Ptr {
@@ -698,7 +847,7 @@ func set(field reflect.Value, sf reflect.StructField, key, value string, isDefau
 
 	switch field.Kind() {
 	case reflect.Slice:
-		return handleSlice(field, key, value, isDefault, sf, funcMap, onSet)
+		return handleSlice(field, key, value, isDefault, sf, funcMap, layout, onSet)
 	case reflect.Map:
 		return handleMap(field, key, value, isDefault, sf, funcMap, onSet)
 	}
@@ -706,19 +855,24 @@ func set(field reflect.Value, sf reflect.StructField, key, value string, isDefau
 	return newNoParserError(sf)
 }
 
-func handleSlice(field reflect.Value, key, value string, isDefault bool, sf reflect.StructField, funcMap map[reflect.Type]ParserFunc, onSet OnSetFn) error {
+func handleSlice(field reflect.Value, key, value string, isDefault bool, sf reflect.StructField, funcMap map[reflect.Type]ParserFunc, layout bool, onSet OnSetFn) error {
 	separator := sf.Tag.Get("envSeparator")
 	if separator == "" {
 		separator = ","
 	}
-	parts := strings.Split(value, separator)
 
+	return handleSliceParts(field, key, strings.Split(value, separator), isDefault, sf, funcMap, layout, onSet)
+}
+
+// handleSliceParts parses already split slice items, e.g. from separated value
+// or from indexed variables.
+func handleSliceParts(field reflect.Value, key string, parts []string, isDefault bool, sf reflect.StructField, funcMap map[reflect.Type]ParserFunc, layout bool, onSet OnSetFn) error {
 	typee := sf.Type.Elem()
 	if typee.Kind() == reflect.Ptr {
 		typee = typee.Elem()
 	}
 
-	if _, ok := reflect.New(typee).Interface().(encoding.TextUnmarshaler); ok {
+	if _, ok := reflect.New(typee).Interface().(encoding.TextUnmarshaler); ok && !(layout && hasParser(funcMap, typee)) {
 		return parseTextUnmarshalers(field, key, parts, sf, onSet)
 	}
 
@@ -802,6 +956,19 @@ func handleMap(field reflect.Value, key, value string, isDefault bool, sf reflec
 	return nil
 }
 
+// hasParser reports whether funcMap has parser of typ, or of its element, if
+// typ is a pointer.
+func hasParser(funcMap map[reflect.Type]ParserFunc, typ reflect.Type) bool {
+	if _, ok := funcMap[typ]; ok {
+		return true
+	}
+	if typ.Kind() != reflect.Ptr {
+		return false
+	}
+	_, ok := funcMap[typ.Elem()]
+	return ok
+}
+
 func asTextUnmarshaler(field reflect.Value) encoding.TextUnmarshaler {
 	if field.Kind() == reflect.Ptr {
 		if field.IsNil() {
@@ -16,6 +16,8 @@ import (
 // VarIsNotSetError
 // EmptyVarError
 // LoadFileContentError
+// InvalidPEMError
+// ResolveSecretError
 // ParseValueError
 type AggregateError struct {
 	Errors []error
@@ -71,6 +73,8 @@ func (e ParseError) Error() string {
 	return fmt.Sprintf("parse error on field %q of type %q: %v", e.Name, e.Type, e.Err)
 }
 
+func (e ParseError) Unwrap() error { return e.Err }
+
 // NotStructPtrError occurs when pass something that is not a pointer to a struct to Parse.
 type NotStructPtrError struct{}
 
@@ -158,6 +162,37 @@ func (e LoadFileContentError) Error() string {
 	return fmt.Sprintf("could not load content of file %q from variable %s: %v", e.Filename, e.Key, e.Err)
 }
 
+// InvalidPEMError occurs when value of "pem" field is neither a raw PEM, nor
+// base64 encoded one, nor a path to PEM file.
+type InvalidPEMError struct {
+	Key string
+}
+
+func newInvalidPEMError(key string) error {
+	return InvalidPEMError{key}
+}
+
+func (e InvalidPEMError) Error() string {
+	return fmt.Sprintf("environment variable %q is not a PEM, base64 encoded PEM or PEM file path", e.Key)
+}
+
+// ResolveSecretError occurs when secret reference in value of "secret" field
+// can't be resolved.
+type ResolveSecretError struct {
+	Key string
+	Err error
+}
+
+func newResolveSecretError(key string, err error) error {
+	return ResolveSecretError{key, err}
+}
+
+func (e ResolveSecretError) Error() string {
+	return fmt.Sprintf("could not resolve secrets of variable %s: %v", e.Key, e.Err)
+}
+
+func (e ResolveSecretError) Unwrap() error { return e.Err }
+
 // ParseValueError occurs when it's impossible to convert value using given parser.
 type ParseValueError struct {
 	Msg string
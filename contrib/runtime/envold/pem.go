@@ -0,0 +1,47 @@
+package env
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// getPEM normalizes value of "pem" field to PEM. Value is detected in order:
+// raw PEM, base64 encoded PEM, path to PEM file.
+func getPEM(key, value string) (string, error) {
+	if isPEM([]byte(value)) {
+		return value, nil
+	}
+
+	// multi-line base64 blobs are common, e.g. from `base64 cert.pem`.
+	compact := strings.Join(strings.Fields(value), "")
+	if decoded, err := base64.StdEncoding.DecodeString(compact); err == nil && isPEM(decoded) {
+		return string(decoded), nil
+	}
+
+	content, err := os.ReadFile(value)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "", newInvalidPEMError(key)
+	case err != nil:
+		return "", newLoadFileContentError(value, key, err)
+	case !isPEM(content):
+		return "", newInvalidPEMError(key)
+	}
+
+	return string(content), nil
+}
+
+func isPEM(data []byte) bool {
+	if !bytes.Contains(data, []byte("-----BEGIN ")) {
+		return false
+	}
+
+	block, _ := pem.Decode(data)
+
+	return block != nil
+}
@@ -0,0 +1,47 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	secretRefPrefix = "${secret:"
+	secretRefSuffix = "}"
+)
+
+var errNoSecretGetter = errors.New("secrets are not available, Options.GetSecret is not set")
+
+// resolveSecrets replaces every ${secret:key} reference in value with the
+// secret returned by getSecret for the key.
+func resolveSecrets(value string, getSecret func(key string) ([]byte, error)) (string, error) {
+	var res strings.Builder
+
+	for {
+		before, after, ok := strings.Cut(value, secretRefPrefix)
+		if !ok {
+			res.WriteString(value)
+
+			return res.String(), nil
+		}
+
+		key, rest, ok := strings.Cut(after, secretRefSuffix)
+		if !ok {
+			return "", fmt.Errorf("unterminated secret reference %q", secretRefPrefix+after)
+		}
+
+		if getSecret == nil {
+			return "", fmt.Errorf("resolving secret %q: %w", key, errNoSecretGetter)
+		}
+
+		data, err := getSecret(key)
+		if err != nil {
+			return "", fmt.Errorf("resolving secret %q: %w", key, err)
+		}
+
+		res.WriteString(before)
+		res.Write(data)
+		value = rest
+	}
+}
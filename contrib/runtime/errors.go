@@ -0,0 +1,138 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/quenbyako/core"
+	envold "github.com/quenbyako/core/contrib/runtime/envold"
+)
+
+// Lifecycle phases, reported with errors, see [LifecycleError.Phase].
+const (
+	PhaseEnv       = "env"
+	PhaseValidate  = "validate"
+	PhaseSetup     = "setup"
+	PhaseConfigure = "configure"
+	PhaseAcquire   = "acquire"
+	PhaseServe     = "serve"
+	PhaseDrain     = "drain"
+	PhaseShutdown  = "shutdown"
+)
+
+// LifecycleError is an error of lifecycle phase, caused by param or by the
+// runtime itself. Errors returned by [RunE] could be inspected with
+// [errors.As] to classify failures.
+type LifecycleError struct {
+	phase string
+	// type of the failed param, empty if the runtime itself failed.
+	paramType string
+	err       error
+}
+
+func (e *LifecycleError) Error() string { return e.err.Error() }
+func (e *LifecycleError) Unwrap() error { return e.err }
+
+// Phase returns the failed lifecycle phase, e.g. [PhaseAcquire].
+func (e *LifecycleError) Phase() string { return e.phase }
+
+// ParamType returns type of the failed param (e.g. "*grpc.Server"), or empty
+// string, if the runtime itself failed.
+func (e *LifecycleError) ParamType() string { return e.paramType }
+
+// ExitCodeError is returned by [RunE] if the application didn't exit cleanly:
+// either action returned non-zero exit code, or lifecycle failed, then Err
+// holds every [*LifecycleError] joined.
+type ExitCodeError struct {
+	Code core.ExitCode
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("action exited with code %v", e.Code)
+}
+
+func (e *ExitCodeError) Unwrap() error { return e.Err }
+
+// phaseError marks err as failure of phase. param is the failed param, or nil.
+func phaseError(phase string, param any, err error) error {
+	var paramType string
+	if param != nil {
+		paramType = fmt.Sprintf("%T", param)
+	}
+
+	return &LifecycleError{phase: phase, paramType: paramType, err: err}
+}
+
+// envError converts env parsing error into lifecycle errors, collecting all
+// unset variables into a single one.
+func envError(err error) error {
+	// warn: aggregate error is not returned by value, not by pointer
+	aggregate := new(envold.AggregateError)
+	if !errors.As(err, aggregate) {
+		return phaseError(PhaseEnv, nil, err)
+	}
+
+	var (
+		missedFields []string
+		errs         []error
+	)
+
+	for _, err := range aggregate.Errors {
+		if e := new(envold.VarIsNotSetError); errors.As(err, e) {
+			missedFields = append(missedFields, e.Key)
+		} else {
+			errs = append(errs, phaseError(PhaseEnv, nil, err))
+		}
+	}
+
+	if len(missedFields) > 0 {
+		slices.Sort(missedFields)
+
+		errs = append(errs, phaseError(PhaseEnv, nil, fmt.Errorf("missing required environment variables: %v", missedFields)))
+	}
+
+	if len(errs) == 0 {
+		return phaseError(PhaseEnv, nil, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validationError marks every error joined into err as validation failure.
+func validationError(err error) error {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	wrapped := make([]error, len(errs))
+	for i, err := range errs {
+		wrapped[i] = phaseError(PhaseValidate, nil, fmt.Errorf("invalid config: %w", err))
+	}
+
+	return errors.Join(wrapped...)
+}
+
+// reportErrors logs every error joined into err, including nested joins.
+func reportErrors(log LogCallbacks, err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			reportErrors(log, err)
+		}
+
+		return
+	}
+
+	phase, paramType := "", ""
+	if e := (*LifecycleError)(nil); errors.As(err, &e) {
+		phase, paramType = e.phase, e.paramType
+	}
+
+	log.LifecycleFailed(phase, paramType, err)
+}
@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/quenbyako/core"
+)
+
+// newFeatureClient registers the provider declared by config (if any) under
+// the application domain and returns a client bound to it. It returns nil when
+// config does not implement [core.FeaturesConfig] or supplies no provider, so
+// that [core.Features] reports the capability as absent.
+//
+// Registered provider must be released with [featureProvider.shutdown].
+//
+//nolint:ireturn // returns interface on intention.
+func newFeatureClient(config any, name core.AppName) (openfeature.IClient, *featureProvider, error) {
+	cfg, ok := config.(core.FeaturesConfig)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	provider := cfg.GetFeatureProvider()
+	if provider == nil {
+		return nil, nil, nil
+	}
+
+	domain, _ := name.Name()
+	registered := &featureProvider{FeatureProvider: provider, domain: domain}
+	if err := openfeature.SetNamedProviderAndWait(domain, registered); err != nil {
+		return nil, nil, fmt.Errorf("initializing feature provider: %w", err)
+	}
+
+	return openfeature.NewClient(domain), registered, nil
+}
+
+// featureProvider wraps provider registered by the runtime. SDK shuts replaced
+// providers down in background, so the wrapper ignores it, and the runtime
+// shuts the provider down itself, within the shutdown phase.
+type featureProvider struct {
+	openfeature.FeatureProvider
+
+	domain string
+	once   sync.Once
+}
+
+var (
+	_ openfeature.StateHandler = (*featureProvider)(nil)
+	_ openfeature.EventHandler = (*featureProvider)(nil)
+	_ openfeature.Tracker      = (*featureProvider)(nil)
+)
+
+func (p *featureProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	if h, ok := p.FeatureProvider.(openfeature.StateHandler); ok {
+		return h.Init(evalCtx) //nolint:wrapcheck // transparent wrapper
+	}
+
+	return nil
+}
+
+// Shutdown is called by SDK, see [featureProvider.shutdown].
+func (p *featureProvider) Shutdown() {}
+
+// EventChannel returns nil channel, if provider doesn't emit events: SDK
+// listens to it until provider is replaced.
+func (p *featureProvider) EventChannel() <-chan openfeature.Event {
+	if h, ok := p.FeatureProvider.(openfeature.EventHandler); ok {
+		return h.EventChannel()
+	}
+
+	return nil
+}
+
+func (p *featureProvider) Track(ctx context.Context, name string, evalCtx openfeature.EvaluationContext, details openfeature.TrackingEventDetails) {
+	if t, ok := p.FeatureProvider.(openfeature.Tracker); ok {
+		t.Track(ctx, name, evalCtx, details)
+	}
+}
+
+// shutdown unregisters provider from the domain, then shuts it down. Safe to
+// call on nil provider.
+func (p *featureProvider) shutdown() error {
+	if p == nil {
+		return nil
+	}
+
+	err := openfeature.SetNamedProviderAndWait(p.domain, openfeature.NoopProvider{})
+
+	p.once.Do(func() {
+		if h, ok := p.FeatureProvider.(openfeature.StateHandler); ok {
+			h.Shutdown()
+		}
+	})
+
+	if err != nil {
+		return fmt.Errorf("unregistering feature provider: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/open-feature/go-sdk/openfeature/memprovider"
+	"github.com/quenbyako/core"
+)
+
+// featuresConfig enables feature flags, only if variant of the flag is set.
+type featuresConfig struct {
+	core.UnimplementedActionConfig
+
+	Variant string `env:"FEATURE_NEW_UI" default:""`
+}
+
+//nolint:ireturn // returns interface on intention.
+func (c featuresConfig) GetFeatureProvider() openfeature.FeatureProvider {
+	if c.Variant == "" {
+		return nil
+	}
+
+	return memprovider.NewInMemoryProvider(map[string]memprovider.InMemoryFlag{
+		"new-ui": {
+			Key:            "new-ui",
+			State:          memprovider.Enabled,
+			DefaultVariant: c.Variant,
+			Variants:       map[string]any{"on": true, "off": false},
+		},
+	})
+}
+
+func TestNewFeatureClient(t *testing.T) {
+	t.Run("no features config", func(t *testing.T) {
+		client, provider, err := newFeatureClient(fakeConfig{}, core.NewAppName("features-none", ""))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if client != nil || provider != nil {
+			t.Error("expected no client")
+		}
+
+		if err := provider.shutdown(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("nil provider", func(t *testing.T) {
+		client, provider, err := newFeatureClient(featuresConfig{}, core.NewAppName("features-nil", ""))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if client != nil || provider != nil {
+			t.Error("expected no client")
+		}
+	})
+
+	t.Run("domains", func(t *testing.T) {
+		first, firstProvider, err := newFeatureClient(featuresConfig{Variant: "on"}, core.NewAppName("features-first", ""))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = firstProvider.shutdown() })
+
+		second, secondProvider, err := newFeatureClient(featuresConfig{Variant: "off"}, core.NewAppName("features-second", ""))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = secondProvider.shutdown() })
+
+		if got := first.Boolean(t.Context(), "new-ui", false, openfeature.EvaluationContext{}); !got {
+			t.Error("expected flag of first domain to be enabled")
+		}
+
+		if got := second.Boolean(t.Context(), "new-ui", true, openfeature.EvaluationContext{}); got {
+			t.Error("expected flag of second domain to be disabled")
+		}
+
+		if got := first.Boolean(t.Context(), "missing", true, openfeature.EvaluationContext{}); !got {
+			t.Error("expected default value of unknown flag")
+		}
+	})
+}
+
+// statefulProvider counts lifecycle calls of the provider.
+type statefulProvider struct {
+	openfeature.NoopProvider
+
+	inits, shutdowns atomic.Int32
+}
+
+func (p *statefulProvider) Init(openfeature.EvaluationContext) error {
+	p.inits.Add(1)
+
+	return nil
+}
+
+func (p *statefulProvider) Shutdown() { p.shutdowns.Add(1) }
+
+type statefulConfig struct {
+	core.UnimplementedActionConfig
+
+	provider *statefulProvider
+}
+
+//nolint:ireturn // returns interface on intention.
+func (c statefulConfig) GetFeatureProvider() openfeature.FeatureProvider { return c.provider }
+
+func TestFeatureProviderShutdown(t *testing.T) {
+	provider := &statefulProvider{}
+	name := core.NewAppName("features-shutdown", "")
+
+	_, registered, err := newFeatureClient(statefulConfig{provider: provider}, name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := provider.inits.Load(); got != 1 {
+		t.Errorf("expected provider to be initialized once, got %v", got)
+	}
+
+	for range 2 {
+		if err := registered.shutdown(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := provider.shutdowns.Load(); got != 1 {
+		t.Errorf("expected provider to be shut down once, got %v", got)
+	}
+
+	if got := openfeature.NamedProviderMetadata("features-shutdown").Name; got != (openfeature.NoopProvider{}).Metadata().Name {
+		t.Errorf("expected domain to be released, got provider %q", got)
+	}
+}
+
+func TestRunFeatures(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		captureStderr(t)
+
+		t.Setenv("FEATURE_NEW_UI", "on")
+
+		ctx := core.WithAppName(t.Context(), core.NewAppName("features-run", ""))
+
+		code := Run(func(_ context.Context, appCtx core.AppContext[featuresConfig]) core.ExitCode {
+			client, ok := core.Features(appCtx)
+			if !ok {
+				t.Fatal("expected feature client")
+			}
+
+			if !client.Boolean(t.Context(), "new-ui", false, openfeature.EvaluationContext{}) {
+				t.Error("expected flag to be enabled")
+			}
+
+			return 0
+		})(ctx, nil)
+		if code != 0 {
+			t.Fatalf("expected exit code 0, got %v", code)
+		}
+
+		if got := openfeature.NamedProviderMetadata("features-run").Name; got != (openfeature.NoopProvider{}).Metadata().Name {
+			t.Errorf("expected provider to be released after run, got %q", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		captureStderr(t)
+
+		code := Run(func(_ context.Context, appCtx core.AppContext[featuresConfig]) core.ExitCode {
+			if _, ok := core.Features(appCtx); ok {
+				t.Error("expected no feature client")
+			}
+
+			return 0
+		})(t.Context(), nil)
+		if code != 0 {
+			t.Fatalf("expected exit code 0, got %v", code)
+		}
+	})
+}
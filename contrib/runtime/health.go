@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/quenbyako/core"
+)
+
+// healthRegistry keeps named health checks registered by the action, shared
+// with the readiness probe of the metrics server.
+type healthRegistry struct {
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+type namedCheck struct {
+	name  string
+	check func(context.Context) error
+}
+
+var _ core.HealthRegistry = (*healthRegistry)(nil)
+
+func (h *healthRegistry) RegisterHealthCheck(name string, check func(context.Context) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// failing runs every check, returning names of failed ones in registration
+// order. Nil registry has no checks.
+func (h *healthRegistry) failing(ctx context.Context) []string {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var res []string
+	for _, c := range h.checks {
+		if err := c.check(ctx); err != nil {
+			res = append(res, c.name)
+		}
+	}
+
+	return res
+}
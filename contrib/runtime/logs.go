@@ -3,16 +3,28 @@ package runtime
 import (
 	"log/slog"
 	"net"
+	"os"
+
+	"github.com/quenbyako/core"
 )
 
 const (
 	eventEffectiveEnvironment = "notify.effective_environment"
+	eventStartupSummary       = "notify.startup_summary"
 )
 
 type LogCallbacks interface {
 	EffectiveEnvironment(env map[string]string)
+	StartupSummary(s StartupSummary)
+	InvalidVersion(v core.AppVersion)
 	MetricsStarted(addr net.Addr)
 	MetricsStopped(addr net.Addr)
+	DrainStarted(sig os.Signal)
+	Cancelled(cause error)
+	// phase is one of lifecycle phases (env, validate, setup, configure,
+	// acquire, serve, drain, shutdown), paramType is empty, if error isn't
+	// caused by a param.
+	LifecycleFailed(phase, paramType string, err error)
 }
 
 type logger struct {
@@ -37,6 +49,36 @@ func (l *logger) EffectiveEnvironment(env map[string]string) {
 	)
 }
 
+func (l *logger) StartupSummary(s StartupSummary) {
+	l.log.Info(
+		"Application started",
+		slog.String("event_type", eventStartupSummary),
+		slog.Any("context", map[string]any{
+			"metrics":  s.Metrics,
+			"traces":   s.Traces,
+			"features": s.Features,
+			"secrets":  s.Secrets,
+			"servers":  s.Servers,
+		}),
+	)
+}
+
+func (l *logger) InvalidVersion(v core.AppVersion) {
+	_, versionValid := v.Version()
+	_, commitValid := v.CommitHash()
+	_, dateValid := v.Date()
+
+	l.log.Warn(
+		"Application version is invalid, telemetry may report malformed version",
+		slog.Any("context", map[string]any{
+			"version":       v.String(),
+			"version_valid": versionValid,
+			"commit_valid":  commitValid,
+			"date_valid":    dateValid,
+		}),
+	)
+}
+
 func (l *logger) MetricsStarted(addr net.Addr) {
 	l.log.Info(
 		"Metrics server started",
@@ -54,3 +96,30 @@ func (l *logger) MetricsStopped(addr net.Addr) {
 		}),
 	)
 }
+
+func (l *logger) DrainStarted(sig os.Signal) {
+	l.log.Info(
+		"Drain started",
+		slog.Any("context", map[string]any{
+			"signal": sig.String(),
+		}),
+	)
+}
+
+func (l *logger) Cancelled(cause error) {
+	l.log.Info(
+		"Context is cancelled before start, nothing is acquired",
+		slog.Any("context", map[string]any{
+			"cause": cause.Error(),
+		}),
+	)
+}
+
+func (l *logger) LifecycleFailed(phase, paramType string, err error) {
+	l.log.Error(
+		"Lifecycle failed",
+		slog.String("phase", phase),
+		slog.String("param_type", paramType),
+		slog.String("error", err.Error()),
+	)
+}
@@ -2,40 +2,87 @@ package observability
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/quenbyako/core"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/metric"
 	noopMetric "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
 
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/semconv/v1.37.0"
 	"go.opentelemetry.io/otel/trace"
 	noopTrace "go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/credentials"
 )
 
 type metrics struct {
 	slog.Handler
 	trace.TracerProvider
 	metric.MeterProvider
+
+	loggerProvider log.LoggerProvider
+}
+
+// Shutdown flushes pending telemetry (e.g. spans queued by batch processor)
+// and stops providers. [core.Metrics] is unusable after call.
+func (m *metrics) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	// noop providers have nothing to flush.
+	for _, provider := range []any{m.TracerProvider, m.MeterProvider, m.loggerProvider} {
+		if s, ok := provider.(interface {
+			Shutdown(ctx context.Context) error
+		}); ok {
+			if err := s.Shutdown(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 type newParams struct {
-	logWriter    io.Writer
-	otelAddr     *url.URL
-	metricReader sdkmetric.Reader
-	hostname     string
-	appVersion   core.AppVersion
-	logLevel     slog.Level
+	logWriter     io.Writer
+	otelAddr      *url.URL
+	otelMetrics   *url.URL
+	otelLogs      *url.URL
+	otelHeaders   otelHeaders
+	compression   string
+	sampler       sdktrace.Sampler
+	propagator    propagation.TextMapPropagator
+	resourceAttrs []attribute.KeyValue
+	environment   string
+	metricReader  sdkmetric.Reader
+	hostname      string
+	appVersion    core.AppVersion
+	logLevel      slog.Level
+	logFormat     LogFormat
+	certPool      *x509.CertPool
 }
 
 func (p *newParams) validate() error {
@@ -43,11 +90,39 @@ func (p *newParams) validate() error {
 		return errors.New("log writer is nil")
 	}
 
+	switch p.logFormat {
+	case LogFormatJSON, LogFormatText:
+	default:
+		return fmt.Errorf("unsupported log format %q", p.logFormat)
+	}
+
+	switch p.compression {
+	case compressionNone, compressionGzip:
+	default:
+		return fmt.Errorf("unsupported OTLP compression %q", p.compression)
+	}
+
 	return nil
 }
 
 type NewOption func(*newParams)
 
+// LogFormat selects the log handler used by [New].
+type LogFormat string
+
+const (
+	// LogFormatJSON writes logs with [slog.JSONHandler].
+	LogFormatJSON LogFormat = "json"
+	// LogFormatText writes logs with [slog.TextHandler], which is easier to
+	// read in terminal.
+	LogFormatText LogFormat = "text"
+)
+
+// WithLogFormat sets the log format, [LogFormatJSON] by default.
+func WithLogFormat(format LogFormat) NewOption {
+	return func(m *newParams) { m.logFormat = format }
+}
+
 func WithLogWriter(writer io.Writer) NewOption {
 	return func(m *newParams) { m.logWriter = writer }
 }
@@ -60,6 +135,106 @@ func WithOtelAddr(otelAddr *url.URL) NewOption {
 	return func(m *newParams) { m.otelAddr = otelAddr }
 }
 
+// WithResourceAttributes adds attributes (e.g. deployment environment, region
+// or team) to the resource of all emitted telemetry. On collision they take
+// precedence over the default ones.
+func WithResourceAttributes(attrs ...attribute.KeyValue) NewOption {
+	return func(m *newParams) { m.resourceAttrs = append(m.resourceAttrs, attrs...) }
+}
+
+// WithEnvironment tags all telemetry with deployment environment (e.g.
+// "prod", "staging" or "dev"): resource of traces and metrics gets
+// "deployment.environment.name" attribute, and logs get "environment" one.
+// Attribute set by [WithResourceAttributes] takes precedence.
+func WithEnvironment(env string) NewOption {
+	return func(m *newParams) { m.environment = env }
+}
+
+// WithTraceSampler sets the sampler of exported traces. By default every span
+// is sampled.
+func WithTraceSampler(sampler sdktrace.Sampler) NewOption {
+	return func(m *newParams) { m.sampler = sampler }
+}
+
+// WithTraceSampleRatio samples the given fraction of traces, respecting the
+// sampling decision of the parent span, if any.
+func WithTraceSampleRatio(ratio float64) NewOption {
+	return WithTraceSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)))
+}
+
+// WithPropagator sets the propagator used to carry trace context and baggage
+// across service boundaries, e.g. B3 propagator from
+// go.opentelemetry.io/contrib/propagators/b3. By default W3C tracecontext and
+// baggage are used.
+//
+// Propagator is set globally with [otel.SetTextMapPropagator], so it affects
+// the whole process, not only the returned [core.Metrics].
+func WithPropagator(propagator propagation.TextMapPropagator) NewOption {
+	return func(m *newParams) { m.propagator = propagator }
+}
+
+// WithOtelMetrics enables pushing metrics to OTLP collector at the given
+// address. Scheme selects the protocol the same way as for [WithOtelAddr]:
+// "http"/"https" or "grpc". It can be combined with [WithMetricReader].
+func WithOtelMetrics(addr *url.URL) NewOption {
+	return func(m *newParams) { m.otelMetrics = addr }
+}
+
+// WithOtelLogs enables exporting logs to OTLP collector at the given address,
+// in addition to the log writer. Scheme selects the protocol the same way as
+// for [WithOtelAddr]: "http"/"https" or "grpc". Exported records carry trace
+// and span IDs of the context they are logged with, so they correlate with
+// traces. Log level applies to exported records as well.
+func WithOtelLogs(addr *url.URL) NewOption {
+	return func(m *newParams) { m.otelLogs = addr }
+}
+
+// WithOtelHeaders sets headers (e.g. "Authorization" or API keys) sent with
+// every OTLP export of traces, metrics and logs, over both http and grpc. Header
+// values are redacted, if params are logged.
+func WithOtelHeaders(headers map[string]string) NewOption {
+	return func(m *newParams) {
+		if m.otelHeaders == nil {
+			m.otelHeaders = make(otelHeaders, len(headers))
+		}
+
+		maps.Copy(m.otelHeaders, headers)
+	}
+}
+
+// OTLP compressions, see [WithOtelCompression].
+const (
+	compressionNone = "none"
+	compressionGzip = "gzip"
+)
+
+// WithOtelCompression sets compression of OTLP exports, "gzip" (default) or
+// "none".
+func WithOtelCompression(compression string) NewOption {
+	return func(m *newParams) { m.compression = compression }
+}
+
+// otelHeaders are OTLP export headers, usually containing credentials.
+type otelHeaders map[string]string
+
+var _ slog.LogValuer = otelHeaders(nil) //nolint:grouper // type check
+
+// LogValue lists header names only, hiding their values.
+func (h otelHeaders) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for _, name := range slices.Sorted(maps.Keys(h)) {
+		attrs = append(attrs, slog.String(name, "REDACTED"))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// WithCertPool sets CA certificates used to verify OTLP collectors over TLS
+// ("https" and "grpcs" schemes). If unset, system pool is used.
+func WithCertPool(pool *x509.CertPool) NewOption {
+	return func(m *newParams) { m.certPool = pool }
+}
+
 func WithHostname(hostname string) NewOption {
 	return func(m *newParams) { m.hostname = hostname }
 }
@@ -68,78 +243,243 @@ func WithMetricReader(reader sdkmetric.Reader) NewOption {
 	return func(m *newParams) { m.metricReader = reader }
 }
 
-// New creates a new observability Metrics instance
+// New creates a new observability Metrics instance. It also implements
+// "Shutdown(context.Context) error", which must be called on exit to flush
+// pending telemetry.
 //
 //nolint:ireturn // returns interface on intention.
 func New(ctx context.Context, opts ...NewOption) (core.Metrics, error) {
 	appName, _ := core.AppNameFromContext(ctx)
+
+	params, err := buildParams(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceAttrs := params.resourceAttrs
+	if params.environment != "" {
+		resourceAttrs = append([]attribute.KeyValue{semconv.DeploymentEnvironmentName(params.environment)}, resourceAttrs...)
+	}
+
+	appResource, err := newResource(appName, params.appVersion, resourceAttrs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTel resource: %w", err)
+	}
+
+	tracerProvider, err := newTraceProvider(ctx, params.otelAddr, params.logWriter, params.sampler, params.certPool, params.otelHeaders, params.compression, appResource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace provider: %w", err)
+	}
+
+	meterProvider, err := newMeterProvider(ctx, params.metricReader, params.otelMetrics, params.certPool, params.otelHeaders, params.compression, appResource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create meter provider: %w", err)
+	}
+
+	loggerProvider, err := newLoggerProvider(ctx, params.otelLogs, params.certPool, params.otelHeaders, params.compression, appResource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger provider: %w", err)
+	}
+
+	otel.SetTextMapPropagator(withPropagator(params.propagator))
+
+	return &metrics{
+		Handler:        params.logHandler(appName, loggerProvider),
+		TracerProvider: tracerProvider,
+		MeterProvider:  meterProvider,
+		loggerProvider: loggerProvider,
+	}, nil
+}
+
+// NewNoop creates Metrics with no-op tracer and meter providers, skipping
+// construction of OTel SDK entirely, so it's cheap enough for tests and
+// applications with disabled telemetry. Logs are discarded, unless
+// [WithLogWriter] is set. Options configuring exporters are ignored.
+//
+//nolint:ireturn // returns interface on intention.
+func NewNoop(ctx context.Context, opts ...NewOption) (core.Metrics, error) {
+	appName, _ := core.AppNameFromContext(ctx)
+
+	params, err := buildParams(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// spans are not recorded, but trace context is still propagated.
+	otel.SetTextMapPropagator(withPropagator(params.propagator))
+
+	return &metrics{
+		Handler:        params.logHandler(appName, nil),
+		TracerProvider: noopTrace.NewTracerProvider(),
+		MeterProvider:  noopMetric.NewMeterProvider(),
+	}, nil
+}
+
+func buildParams(ctx context.Context, opts []NewOption) (newParams, error) {
 	version, _ := core.VersionFromContext(ctx)
 
 	params := newParams{
-		appVersion: version,
-		logWriter:  io.Discard,
-		logLevel:   slog.LevelInfo,
-		otelAddr:   nil,
-		hostname:   "",
+		appVersion:  version,
+		logWriter:   io.Discard,
+		logLevel:    slog.LevelInfo,
+		logFormat:   LogFormatJSON,
+		compression: compressionGzip,
+		otelAddr:    nil,
+		hostname:    "",
 	}
 	for _, opt := range opts {
 		opt(&params)
 	}
 
 	if err := params.validate(); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+		return newParams{}, fmt.Errorf("invalid parameters: %w", err)
 	}
 
-	appResource, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName(ignoreError(appName.Name())),
-			semconv.ServiceVersion(ignoreError(version.VersionCommit())),
-		),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create OTel resource: %w", err)
+	return params, nil
+}
+
+// logHandler builds handler writing to the log writer, also forwarding
+// records to loggerProvider, if it's not nil.
+//
+//nolint:ireturn // returns interface on intention.
+func (p *newParams) logHandler(appName core.AppName, loggerProvider log.LoggerProvider) slog.Handler {
+	local := p.localLogHandler(appName)
+	if loggerProvider == nil {
+		return local
+	}
+
+	exported := &levelHandler{
+		Handler: otelslog.NewHandler(ignoreError(appName.Name()), otelslog.WithLoggerProvider(loggerProvider)),
+		level:   p.logLevel,
+	}
+
+	if local == slog.DiscardHandler {
+		return exported
+	}
+
+	return core.NewTeeHandler(local, exported)
+}
+
+//nolint:ireturn // returns interface on intention.
+func (p *newParams) localLogHandler(appName core.AppName) slog.Handler {
+	if p.logWriter == io.Discard {
+		return slog.DiscardHandler
 	}
 
 	constantAttrs := []slog.Attr{
-		slog.String("service_name", ignoreError(appName.Name())+"@"+version.String()),
-		slog.String("hostname", params.hostname),
+		slog.String("service_name", ignoreError(appName.Name())+"@"+p.appVersion.String()),
+		slog.String("hostname", p.hostname),
+	}
+	if p.environment != "" {
+		constantAttrs = append(constantAttrs, slog.String("environment", p.environment))
 	}
 
-	logHandler := slog.NewJSONHandler(params.logWriter, &slog.HandlerOptions{
-		Level: params.logLevel,
+	handler := newLogHandler(p.logWriter, p.logFormat, &slog.HandlerOptions{
+		Level: p.logLevel,
 		// anything that is lower info, but not included
-		AddSource:   params.logLevel < slog.LevelInfo-1,
+		AddSource:   p.logLevel < slog.LevelInfo-1,
 		ReplaceAttr: nil,
 	}).WithAttrs(constantAttrs)
 
-	tracerProvider, err := newTraceProvider(ctx, params.otelAddr, appResource)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create trace provider: %w", err)
+	// without tracing spans are never recording, so nothing to correlate.
+	if p.otelAddr == nil {
+		return handler
 	}
 
-	var meterProvider metric.MeterProvider = noopMetric.NewMeterProvider()
-	if params.metricReader != nil {
-		meterProvider = sdkmetric.NewMeterProvider(
-			sdkmetric.WithResource(appResource),
-			sdkmetric.WithReader(params.metricReader),
+	return &traceHandler{Handler: handler}
+}
+
+//nolint:ireturn // returns interface on intention.
+func newLogHandler(w io.Writer, format LogFormat, opts *slog.HandlerOptions) slog.Handler {
+	if format == LogFormatText {
+		return slog.NewTextHandler(w, opts)
+	}
+
+	return slog.NewJSONHandler(w, opts)
+}
+
+// levelHandler drops records below level.
+type levelHandler struct {
+	slog.Handler
+
+	level slog.Level
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.Handler.Enabled(ctx, level)
+}
+
+//nolint:ireturn // returns interface on intention.
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+//nolint:ireturn // returns interface on intention.
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// traceHandler adds "trace_id" and "span_id" attributes of the recording span
+// in the context to records, correlating logs with traces.
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h *traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		spanCtx := span.SpanContext()
+
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String("trace_id", spanCtx.TraceID().String()),
+			slog.String("span_id", spanCtx.SpanID().String()),
 		)
 	}
 
-	return &metrics{
-		Handler:        logHandler,
-		TracerProvider: tracerProvider,
-		MeterProvider:  meterProvider,
-	}, nil
+	return h.Handler.Handle(ctx, r) //nolint:wrapcheck // transparent wrapper
+}
+
+//nolint:ireturn // returns interface on intention.
+func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &traceHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+//nolint:ireturn // returns interface on intention.
+func (h *traceHandler) WithGroup(name string) slog.Handler {
+	return &traceHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func newResource(appName core.AppName, version core.AppVersion, attrs []attribute.KeyValue) (*resource.Resource, error) {
+	appResource, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(ignoreError(appName.Name())),
+			semconv.ServiceVersion(ignoreError(version.VersionCommit())),
+		),
+	)
+	if err != nil || len(attrs) == 0 {
+		return appResource, err //nolint:wrapcheck // wrapped by caller
+	}
+
+	// second resource wins on collisions.
+	return resource.Merge(appResource, resource.NewSchemaless(attrs...)) //nolint:wrapcheck // wrapped by caller
 }
 
 // newTraceProvider creates a new trace.TracerProvider based on the provided address.
 //
+// "stdout" and "console" schemes print spans to the log writer synchronously,
+// which is handy for local debugging.
+//
 //nolint:ireturn // returns interface on intention.
 func newTraceProvider(
 	ctx context.Context,
 	addr *url.URL,
+	logWriter io.Writer,
+	sampler sdktrace.Sampler,
+	pool *x509.CertPool,
+	headers map[string]string,
+	compression string,
 	appResource *resource.Resource,
 ) (
 	trace.TracerProvider,
@@ -158,20 +498,48 @@ func newTraceProvider(
 	case "http", "https":
 		opts := []otlptracehttp.Option{
 			otlptracehttp.WithEndpointURL(addr.String()),
+			otlptracehttp.WithHeaders(headers),
+		}
+
+		if compression == compressionGzip {
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
 		}
 
-		if scheme == "https" {
-			opts = append(opts, otlptracehttp.WithTLSClientConfig(nil))
+		if tlsConfig, ok := exporterTLS(addr, pool); ok {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
 		}
 
 		exporter, err = otlptracehttp.New(ctx, opts...)
 
-	case "grpc":
-		exporter, err = otlptracegrpc.New(
-			ctx,
+	case "stdout", "console":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(logWriter))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		}
+
+		return sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter)),
+			sdktrace.WithResource(appResource),
+			withSampler(sampler),
+		), nil
+
+	case "grpc", "grpcs":
+		opts := []otlptracegrpc.Option{
 			otlptracegrpc.WithEndpoint(addr.Host),
-			otlptracegrpc.WithInsecure(),
-		)
+			otlptracegrpc.WithHeaders(headers),
+		}
+
+		if compression == compressionGzip {
+			opts = append(opts, otlptracegrpc.WithCompressor(compressionGzip))
+		}
+
+		if tlsConfig, ok := exporterTLS(addr, pool); ok {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		} else {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err = otlptracegrpc.New(ctx, opts...)
 
 	default:
 		return nil, fmt.Errorf("unsupported trace exporter protocol: %s", scheme)
@@ -189,7 +557,240 @@ func newTraceProvider(
 			sdktrace.WithBatchTimeout(sdktrace.DefaultScheduleDelay*time.Millisecond),
 		),
 		sdktrace.WithResource(appResource),
+		withSampler(sampler),
+	), nil
+}
+
+// withSampler keeps SDK default sampler if sampler is nil.
+//
+//nolint:ireturn // returns interface on intention.
+func withSampler(sampler sdktrace.Sampler) sdktrace.TracerProviderOption {
+	if sampler == nil {
+		sampler = sdktrace.ParentBased(sdktrace.AlwaysSample())
+	}
+
+	return sdktrace.WithSampler(sampler)
+}
+
+// withPropagator falls back to W3C tracecontext and baggage if propagator is
+// nil.
+//
+//nolint:ireturn // returns interface on intention.
+func withPropagator(propagator propagation.TextMapPropagator) propagation.TextMapPropagator {
+	if propagator == nil {
+		propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+
+	return propagator
+}
+
+// newMeterProvider creates a new metric.MeterProvider, registering both pull
+// reader (if any) and periodic OTLP reader (if addr is set).
+//
+//nolint:ireturn // returns interface on intention.
+func newMeterProvider(
+	ctx context.Context,
+	reader sdkmetric.Reader,
+	addr *url.URL,
+	pool *x509.CertPool,
+	headers map[string]string,
+	compression string,
+	appResource *resource.Resource,
+) (
+	metric.MeterProvider,
+	error,
+) {
+	if reader == nil && addr == nil {
+		return noopMetric.NewMeterProvider(), nil
+	}
+
+	opts := []sdkmetric.Option{
+		sdkmetric.WithResource(appResource),
+	}
+
+	if reader != nil {
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+
+	if addr != nil {
+		exporter, err := newMetricExporter(ctx, addr, pool, headers, compression)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+	}
+
+	return sdkmetric.NewMeterProvider(opts...), nil
+}
+
+// newMetricExporter creates OTLP metric exporter based on the address scheme.
+//
+//nolint:ireturn // returns interface on intention.
+func newMetricExporter(
+	ctx context.Context,
+	addr *url.URL,
+	pool *x509.CertPool,
+	headers map[string]string,
+	compression string,
+) (sdkmetric.Exporter, error) {
+	var (
+		exporter sdkmetric.Exporter
+		err      error
+	)
+
+	switch scheme := addr.Scheme; scheme {
+	case "http", "https":
+		opts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpointURL(addr.String()),
+			otlpmetrichttp.WithHeaders(headers),
+		}
+
+		if compression == compressionGzip {
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		}
+
+		if tlsConfig, ok := exporterTLS(addr, pool); ok {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+		}
+
+		exporter, err = otlpmetrichttp.New(ctx, opts...)
+
+	case "grpc", "grpcs":
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(addr.Host),
+			otlpmetricgrpc.WithHeaders(headers),
+		}
+
+		if compression == compressionGzip {
+			opts = append(opts, otlpmetricgrpc.WithCompressor(compressionGzip))
+		}
+
+		if tlsConfig, ok := exporterTLS(addr, pool); ok {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+
+		exporter, err = otlpmetricgrpc.New(ctx, opts...)
+
+	default:
+		return nil, fmt.Errorf("unsupported metric exporter protocol: %s", scheme)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// newLoggerProvider creates logger provider batching records to OTLP log
+// exporter, or nil if addr is not set.
+//
+//nolint:ireturn // returns interface on intention.
+func newLoggerProvider(
+	ctx context.Context,
+	addr *url.URL,
+	pool *x509.CertPool,
+	headers map[string]string,
+	compression string,
+	appResource *resource.Resource,
+) (
+	log.LoggerProvider,
+	error,
+) {
+	if addr == nil {
+		return nil, nil
+	}
+
+	exporter, err := newLogExporter(ctx, addr, pool, headers, compression)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(appResource),
 	), nil
 }
 
+// newLogExporter creates OTLP log exporter based on the address scheme.
+//
+//nolint:ireturn // returns interface on intention.
+func newLogExporter(
+	ctx context.Context,
+	addr *url.URL,
+	pool *x509.CertPool,
+	headers map[string]string,
+	compression string,
+) (sdklog.Exporter, error) {
+	var (
+		exporter sdklog.Exporter
+		err      error
+	)
+
+	switch scheme := addr.Scheme; scheme {
+	case "http", "https":
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpointURL(addr.String()),
+			otlploghttp.WithHeaders(headers),
+		}
+
+		if compression == compressionGzip {
+			opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+		}
+
+		if tlsConfig, ok := exporterTLS(addr, pool); ok {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(tlsConfig))
+		}
+
+		exporter, err = otlploghttp.New(ctx, opts...)
+
+	case "grpc", "grpcs":
+		opts := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(addr.Host),
+			otlploggrpc.WithHeaders(headers),
+		}
+
+		if compression == compressionGzip {
+			opts = append(opts, otlploggrpc.WithCompressor(compressionGzip))
+		}
+
+		if tlsConfig, ok := exporterTLS(addr, pool); ok {
+			opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		} else {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+
+		exporter, err = otlploggrpc.New(ctx, opts...)
+
+	default:
+		return nil, fmt.Errorf("unsupported log exporter protocol: %s", scheme)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// exporterTLS returns TLS config for OTLP exporter, or false if connection is
+// insecure. TLS is used for "https" and "grpcs" schemes, as well as for
+// "grpc" with "insecure=false" query parameter.
+func exporterTLS(addr *url.URL, pool *x509.CertPool) (*tls.Config, bool) {
+	switch addr.Scheme {
+	case "https", "grpcs":
+	case "grpc":
+		if addr.Query().Get("insecure") != "false" {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, true
+}
+
 func ignoreError[T any, E any](v T, _ E) T { return v }
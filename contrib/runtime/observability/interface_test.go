@@ -0,0 +1,804 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quenbyako/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	noopMetric "go.opentelemetry.io/otel/metric/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+func TestNewMetricExporter(t *testing.T) {
+	for _, tt := range []struct {
+		addr    string
+		wantErr bool
+		check   func(sdkmetric.Exporter) bool
+	}{
+		{addr: "http://collector:4318/v1/metrics", check: isHTTPExporter},
+		{addr: "https://collector:4318/v1/metrics", check: isHTTPExporter},
+		{addr: "grpc://collector:4317", check: isGRPCExporter},
+		{addr: "grpcs://collector:4317", check: isGRPCExporter},
+		{addr: "udp://collector:4317", wantErr: true},
+	} {
+		t.Run(tt.addr, func(t *testing.T) {
+			u, err := url.Parse(tt.addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exporter, err := newMetricExporter(t.Context(), u, nil, nil, compressionGzip)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = exporter.Shutdown(t.Context()) })
+
+			if !tt.check(exporter) {
+				t.Errorf("unexpected exporter %T", exporter)
+			}
+		})
+	}
+}
+
+func TestNewMeterProvider(t *testing.T) {
+	otlp, _ := url.Parse("grpc://collector:4317")
+
+	t.Run("disabled", func(t *testing.T) {
+		provider, err := newMeterProvider(t.Context(), nil, nil, nil, nil, compressionGzip, resource.Empty())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := provider.(noopMetric.MeterProvider); !ok {
+			t.Errorf("expected noop provider, got %T", provider)
+		}
+	})
+
+	t.Run("both readers", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+
+		provider, err := newMeterProvider(t.Context(), reader, otlp, nil, nil, compressionGzip, resource.Empty())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sdkProvider, ok := provider.(*sdkmetric.MeterProvider)
+		if !ok {
+			t.Fatalf("expected sdk provider, got %T", provider)
+		}
+		t.Cleanup(func() { _ = sdkProvider.Shutdown(t.Context()) })
+
+		// manual reader is registered only if it's bound to the provider.
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(t.Context(), &rm); err != nil {
+			t.Errorf("reader is not registered: %v", err)
+		}
+	})
+}
+
+func TestStdoutTraceProvider(t *testing.T) {
+	for _, scheme := range []string{"stdout", "console"} {
+		t.Run(scheme, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			provider, err := newTraceProvider(t.Context(), &url.URL{Scheme: scheme}, &buf, nil, nil, nil, compressionGzip, resource.Empty())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, span := provider.Tracer("test").Start(t.Context(), "debug-span")
+			span.End()
+
+			// simple span processor exports synchronously, no flush needed.
+			var exported struct{ Name string }
+			if err := json.NewDecoder(&buf).Decode(&exported); err != nil {
+				t.Fatalf("expected JSON span on writer, got %q: %v", buf.String(), err)
+			}
+
+			if exported.Name != "debug-span" {
+				t.Errorf("expected span %q, got %q", "debug-span", exported.Name)
+			}
+		})
+	}
+}
+
+func TestTraceSampleRatio(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		opts    []NewOption
+		sampled bool
+	}{
+		{"default", nil, true},
+		{"ratio 0", []NewOption{WithTraceSampleRatio(0)}, false},
+		{"ratio 1", []NewOption{WithTraceSampleRatio(1)}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			opts := append([]NewOption{WithOtelAddr(&url.URL{Scheme: "stdout"}), WithLogWriter(&buf)}, tt.opts...)
+
+			m, err := New(t.Context(), opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, span := m.Tracer("test").Start(t.Context(), "sampled-span")
+			span.End()
+
+			if got := span.SpanContext().IsSampled(); got != tt.sampled {
+				t.Errorf("expected sampled %v, got %v", tt.sampled, got)
+			}
+
+			if got := bytes.Contains(buf.Bytes(), []byte("sampled-span")); got != tt.sampled {
+				t.Errorf("expected span exported %v, got %v", tt.sampled, got)
+			}
+		})
+	}
+}
+
+func TestResourceAttributes(t *testing.T) {
+	appName := core.NewAppName("billing", "Billing")
+	version := core.NewVersion("v1.2.3", "", "")
+
+	t.Run("custom", func(t *testing.T) {
+		res, err := newResource(appName, version, []attribute.KeyValue{
+			semconv.DeploymentEnvironmentName("staging"),
+			attribute.String("team", "payments"),
+			semconv.ServiceVersion("overridden"),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for key, want := range map[attribute.Key]string{
+			semconv.ServiceNameKey:               "billing",
+			semconv.ServiceVersionKey:            "overridden",
+			semconv.DeploymentEnvironmentNameKey: "staging",
+			"team":                               "payments",
+		} {
+			if got, _ := res.Set().Value(key); got.AsString() != want {
+				t.Errorf("%v: expected %q, got %q", key, want, got.AsString())
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		res, err := newResource(appName, version, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, _ := res.Set().Value(semconv.ServiceNameKey); got.AsString() != "billing" {
+			t.Errorf("expected service name %q, got %q", "billing", got.AsString())
+		}
+
+		if res.SchemaURL() != semconv.SchemaURL {
+			t.Errorf("expected schema %q, got %q", semconv.SchemaURL, res.SchemaURL())
+		}
+	})
+}
+
+func TestLogFormat(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		format LogFormat
+		want   string
+	}{
+		{"default", "", `"msg":"hello"`},
+		{"json", LogFormatJSON, `"msg":"hello"`},
+		{"text", LogFormatText, `msg=hello`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			opts := []NewOption{WithLogWriter(&buf), WithHostname("box")}
+			if tt.format != "" {
+				opts = append(opts, WithLogFormat(tt.format))
+			}
+
+			m, err := New(t.Context(), opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			slog.New(m).Info("hello")
+
+			for _, want := range []string{tt.want, "box"} {
+				if !bytes.Contains(buf.Bytes(), []byte(want)) {
+					t.Errorf("expected %q in log output %q", want, buf.String())
+				}
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, err := New(t.Context(), WithLogFormat("xml")); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestExporterTLS(t *testing.T) {
+	pool := x509.NewCertPool()
+
+	for _, tt := range []struct {
+		addr   string
+		secure bool
+	}{
+		{"http://collector:4318", false},
+		{"https://collector:4318", true},
+		{"grpc://collector:4317", false},
+		{"grpc://collector:4317?insecure=true", false},
+		{"grpc://collector:4317?insecure=false", true},
+		{"grpcs://collector:4317", true},
+	} {
+		t.Run(tt.addr, func(t *testing.T) {
+			u, err := url.Parse(tt.addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tlsConfig, secure := exporterTLS(u, pool)
+			if secure != tt.secure {
+				t.Fatalf("expected secure %v, got %v", tt.secure, secure)
+			}
+
+			if secure && tlsConfig.RootCAs != pool {
+				t.Error("expected configured CA pool")
+			}
+
+			// exporter must accept the options as well.
+			if _, err := newTraceProvider(t.Context(), u, io.Discard, nil, pool, nil, compressionGzip, resource.Empty()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func isHTTPExporter(e sdkmetric.Exporter) bool {
+	_, ok := e.(*otlpmetrichttp.Exporter)
+	return ok
+}
+
+func isGRPCExporter(e sdkmetric.Exporter) bool {
+	_, ok := e.(*otlpmetricgrpc.Exporter)
+	return ok
+}
+
+func TestPropagator(t *testing.T) {
+	propagator := withPropagator(nil)
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:     trace.SpanID{0x04, 0x05},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+
+	member, err := baggage.NewMember("tenant", "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bag, err := baggage.New(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := baggage.ContextWithBaggage(trace.ContextWithSpanContext(t.Context(), spanCtx), bag)
+
+	carrier := propagation.HeaderCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	if carrier.Get("traceparent") == "" {
+		t.Error("traceparent header is not injected")
+	}
+
+	extracted := propagator.Extract(t.Context(), carrier)
+
+	if got := trace.SpanContextFromContext(extracted); !got.Equal(spanCtx) {
+		t.Errorf("expected span context %v, got %v", spanCtx, got)
+	}
+
+	if got := baggage.FromContext(extracted).Member("tenant").Value(); got != "acme" {
+		t.Errorf("expected baggage %q, got %q", "acme", got)
+	}
+
+	t.Run("custom", func(t *testing.T) {
+		custom := propagation.TraceContext{}
+		if got := withPropagator(custom); got != custom {
+			t.Errorf("expected custom propagator, got %T", got)
+		}
+	})
+}
+
+func TestNewNoop(t *testing.T) {
+	m, err := NewNoop(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if m.Enabled(t.Context(), slog.LevelError) {
+		t.Error("expected logs to be discarded")
+	}
+
+	if provider := m.(*metrics).MeterProvider; provider != (noopMetric.MeterProvider{}) {
+		t.Errorf("expected noop meter provider, got %T", provider)
+	}
+
+	if _, span := m.Tracer("test").Start(t.Context(), "span"); span.IsRecording() {
+		t.Error("expected span not to be recorded")
+	}
+
+	t.Run("log writer", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		m, err := NewNoop(t.Context(), WithLogWriter(&buf), WithLogFormat(LogFormatText))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		slog.New(m).Info("hello")
+
+		if !bytes.Contains(buf.Bytes(), []byte("msg=hello")) {
+			t.Errorf("expected log output, got %q", buf.String())
+		}
+	})
+
+	t.Run("allocations", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := NewNoop(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		// SDK construction allocates hundreds of objects.
+		if allocs > 20 {
+			t.Errorf("expected few allocations, got %v", allocs)
+		}
+	})
+}
+
+// otlpExport is what collector received with single OTLP export.
+type otlpExport struct {
+	authorization string
+	compression   string
+}
+
+// otlpCollector starts OTLP collector of scheme ("http" or "grpc"),
+// returning its address and a channel receiving each export.
+func otlpCollector(t *testing.T, scheme string) (*url.URL, <-chan otlpExport) {
+	t.Helper()
+
+	got := make(chan otlpExport, 16)
+
+	if scheme == "http" {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got <- otlpExport{
+				authorization: r.Header.Get("Authorization"),
+				compression:   r.Header.Get("Content-Encoding"),
+			}
+		}))
+		t.Cleanup(srv.Close)
+
+		u, _ := url.Parse(srv.URL)
+
+		return u, got
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := grpc.NewServer(
+		grpc.StatsHandler(headerStats(got)),
+		grpc.UnknownServiceHandler(func(any, grpc.ServerStream) error { return nil }),
+	)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return &url.URL{Scheme: "grpc", Host: lis.Addr().String()}, got
+}
+
+// headerStats reports headers of incoming grpc calls.
+type headerStats chan<- otlpExport
+
+func (h headerStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context   { return ctx }
+func (h headerStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+func (h headerStats) HandleConn(context.Context, stats.ConnStats)                       {}
+
+func (h headerStats) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InHeader); ok {
+		h <- otlpExport{
+			authorization: strings.Join(in.Header.Get("authorization"), ","),
+			compression:   in.Compression,
+		}
+	}
+}
+
+// exportAll exports a span and empty metrics, returning the exports
+// collector (see [otlpCollector]) received.
+func exportAll(t *testing.T, scheme string, opts ...NewOption) (traces, metrics otlpExport) {
+	t.Helper()
+
+	params, err := buildParams(t.Context(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	addr, got := otlpCollector(t, scheme)
+
+	provider, err := newTraceProvider(ctx, addr, io.Discard, nil, nil, params.otelHeaders, params.compression, resource.Empty())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.(*sdktrace.TracerProvider).Shutdown(context.Background()) })
+
+	_, span := provider.Tracer("test").Start(ctx, "span")
+	span.End()
+
+	_ = provider.(*sdktrace.TracerProvider).ForceFlush(ctx)
+	traces = receiveExport(t, got)
+
+	exporter, err := newMetricExporter(ctx, addr, nil, params.otelHeaders, params.compression)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = exporter.Shutdown(context.Background()) })
+
+	_ = exporter.Export(ctx, &metricdata.ResourceMetrics{Resource: resource.Empty()})
+
+	return traces, receiveExport(t, got)
+}
+
+func TestOtelHeaders(t *testing.T) {
+	headers := map[string]string{"Authorization": "Bearer token"}
+
+	for _, scheme := range []string{"http", "grpc"} {
+		t.Run(scheme, func(t *testing.T) {
+			traces, metrics := exportAll(t, scheme, WithOtelHeaders(headers))
+
+			for kind, export := range map[string]otlpExport{"traces": traces, "metrics": metrics} {
+				if export.authorization != "Bearer token" {
+					t.Errorf("%v: expected header %q, got %q", kind, "Bearer token", export.authorization)
+				}
+			}
+		})
+	}
+
+	t.Run("redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		var params newParams
+		WithOtelHeaders(headers)(&params)
+
+		slog.New(slog.NewTextHandler(&buf, nil)).Info("params", "headers", params.otelHeaders)
+
+		if strings.Contains(buf.String(), "token") || !strings.Contains(buf.String(), "headers.Authorization=REDACTED") {
+			t.Fatalf("expected redacted headers, got %q", buf.String())
+		}
+	})
+}
+
+func receiveExport(t *testing.T, got <-chan otlpExport) otlpExport {
+	t.Helper()
+
+	select {
+	case export := <-got:
+		return export
+	case <-time.After(5 * time.Second):
+		t.Fatal("export was not received")
+
+		return otlpExport{}
+	}
+}
+
+func TestOtelCompression(t *testing.T) {
+	for _, scheme := range []string{"http", "grpc"} {
+		for name, tt := range map[string]struct {
+			opts []NewOption
+			want string
+		}{
+			"default": {want: "gzip"},
+			"gzip":    {opts: []NewOption{WithOtelCompression("gzip")}, want: "gzip"},
+			"none":    {opts: []NewOption{WithOtelCompression("none")}, want: ""},
+		} {
+			t.Run(scheme+" "+name, func(t *testing.T) {
+				traces, metrics := exportAll(t, scheme, tt.opts...)
+
+				for kind, export := range map[string]otlpExport{"traces": traces, "metrics": metrics} {
+					// grpc reports "identity" for uncompressed calls.
+					if got := strings.TrimPrefix(export.compression, "identity"); got != tt.want {
+						t.Errorf("%v: expected compression %q, got %q", kind, tt.want, export.compression)
+					}
+				}
+			})
+		}
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		if _, err := New(t.Context(), WithOtelCompression("zstd")); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestEnvironment(t *testing.T) {
+	var buf bytes.Buffer
+
+	reader := sdkmetric.NewManualReader()
+
+	m, err := New(t.Context(),
+		WithEnvironment("staging"),
+		WithLogWriter(&buf),
+		WithOtelAddr(&url.URL{Scheme: "stdout"}),
+		WithMetricReader(reader),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("logs", func(t *testing.T) {
+		buf.Reset()
+		slog.New(m).Info("hello")
+
+		if !bytes.Contains(buf.Bytes(), []byte(`"environment":"staging"`)) {
+			t.Errorf("expected environment in log output %q", buf.String())
+		}
+	})
+
+	t.Run("traces", func(t *testing.T) {
+		buf.Reset()
+
+		_, span := m.Tracer("test").Start(t.Context(), "span")
+		span.End()
+
+		var exported struct {
+			Resource []struct {
+				Key   string
+				Value struct{ Value string }
+			}
+		}
+		if err := json.NewDecoder(&buf).Decode(&exported); err != nil {
+			t.Fatalf("expected JSON span, got %q: %v", buf.String(), err)
+		}
+
+		for _, attr := range exported.Resource {
+			if attr.Key == string(semconv.DeploymentEnvironmentNameKey) && attr.Value.Value == "staging" {
+				return
+			}
+		}
+
+		t.Errorf("expected environment in span resource %+v", exported.Resource)
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(t.Context(), &rm); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got, _ := rm.Resource.Set().Value(semconv.DeploymentEnvironmentNameKey); got.AsString() != "staging" {
+			t.Errorf("expected environment %q, got %q", "staging", got.AsString())
+		}
+	})
+}
+
+// recordingExporter keeps exported log records.
+type recordingExporter struct {
+	records []sdklog.Record
+}
+
+var _ sdklog.Exporter = (*recordingExporter)(nil) //nolint:grouper // type check
+
+func (e *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func TestOtelLogs(t *testing.T) {
+	t.Run("forwards records", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		params, err := buildParams(t.Context(), []NewOption{WithLogWriter(&buf), WithLogLevel(slog.LevelInfo)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		exporter := &recordingExporter{}
+		provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+
+		spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{2},
+		})
+		ctx := trace.ContextWithSpanContext(t.Context(), spanCtx)
+
+		logger := slog.New(params.logHandler(core.AppName{}, provider)).With("user", "alice")
+		logger.InfoContext(ctx, "hello")
+		logger.DebugContext(ctx, "dropped")
+
+		if !strings.Contains(buf.String(), `"msg":"hello"`) {
+			t.Errorf("expected record in log writer, got %q", buf.String())
+		}
+
+		if len(exporter.records) != 1 {
+			t.Fatalf("expected 1 exported record, got %v", len(exporter.records))
+		}
+
+		record := exporter.records[0]
+		if record.Body().AsString() != "hello" {
+			t.Errorf("expected body %q, got %q", "hello", record.Body().AsString())
+		}
+
+		if record.TraceID() != spanCtx.TraceID() || record.SpanID() != spanCtx.SpanID() {
+			t.Errorf("expected record to correlate with span %v, got %v", spanCtx.TraceID(), record.TraceID())
+		}
+
+		var user string
+		record.WalkAttributes(func(kv otellog.KeyValue) bool {
+			if kv.Key == "user" {
+				user = kv.Value.AsString()
+			}
+
+			return true
+		})
+
+		if user != "alice" {
+			t.Errorf("expected attribute user=alice, got %q", user)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		params, err := buildParams(t.Context(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		provider, err := newLoggerProvider(t.Context(), params.otelLogs, nil, nil, params.compression, resource.Empty())
+		if err != nil || provider != nil {
+			t.Fatalf("expected no provider, got %v, %v", provider, err)
+		}
+	})
+
+	for _, scheme := range []string{"http", "grpc"} {
+		t.Run(scheme+" exporter", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			defer cancel()
+
+			addr, got := otlpCollector(t, scheme)
+
+			exporter, err := newLogExporter(ctx, addr, nil, map[string]string{"Authorization": "Bearer token"}, compressionGzip)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = exporter.Shutdown(context.Background()) })
+
+			_ = exporter.Export(ctx, []sdklog.Record{{}})
+
+			if export := receiveExport(t, got); export.authorization != "Bearer token" {
+				t.Errorf("expected header %q, got %q", "Bearer token", export.authorization)
+			}
+		})
+	}
+}
+
+func TestLogTraceIDs(t *testing.T) {
+	var buf bytes.Buffer
+
+	m, err := New(t.Context(), WithLogWriter(&buf), WithOtelAddr(&url.URL{Scheme: "stdout"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, span := m.Tracer("test").Start(t.Context(), "span")
+	buf.Reset()
+	slog.New(m).InfoContext(ctx, "inside")
+	span.End()
+
+	var record struct {
+		Msg     string `json:"msg"`
+		TraceID string `json:"trace_id"`
+		SpanID  string `json:"span_id"`
+	}
+	if err := json.NewDecoder(&buf).Decode(&record); err != nil || record.Msg != "inside" {
+		t.Fatalf("expected JSON record, got %q: %v", buf.String(), err)
+	}
+
+	if spanCtx := span.SpanContext(); record.TraceID != spanCtx.TraceID().String() || record.SpanID != spanCtx.SpanID().String() {
+		t.Errorf("expected trace %v and span %v, got %+v", spanCtx.TraceID(), spanCtx.SpanID(), record)
+	}
+
+	t.Run("without span", func(t *testing.T) {
+		buf.Reset()
+		slog.New(m).InfoContext(t.Context(), "outside")
+
+		if strings.Contains(buf.String(), "trace_id") {
+			t.Errorf("expected no trace ID, got %q", buf.String())
+		}
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	addr, got := otlpCollector(t, "http")
+
+	m, err := New(t.Context(), WithOtelAddr(addr.JoinPath("/v1/traces")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, span := m.Tracer("test").Start(t.Context(), "span")
+	span.End()
+
+	// batch processor exports spans after few seconds, unless flushed.
+	select {
+	case <-got:
+		t.Fatal("span exported before shutdown")
+	default:
+	}
+
+	if err := m.(*metrics).Shutdown(t.Context()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-got:
+	default:
+		t.Fatal("span was not flushed on shutdown")
+	}
+
+	t.Run("noop", func(t *testing.T) {
+		m, err := NewNoop(t.Context())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := m.(*metrics).Shutdown(t.Context()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
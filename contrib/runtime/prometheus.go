@@ -2,16 +2,20 @@ package runtime
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/quenbyako/core"
 	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
@@ -21,20 +25,113 @@ const (
 	defaultReadTimeout       = 5 * time.Second
 	defaultWriteTimeout      = 10 * time.Second
 	defaultIdleTimeout       = 120 * time.Second
+
+	defaultServerStopTimeout = 5 * time.Second
 )
 
 type promhttpWrapper struct {
 	log  LogCallbacks
 	addr net.Addr
+	// nil for plaintext http.
+	config *tls.Config
+	useTLS bool
+
+	paths    probePaths
+	registry prometheus.Gatherer
+	ready    func(context.Context) bool
+
+	checksMu sync.RWMutex
+	checks   []func(context.Context) bool
+	// named checks, reported by readiness probe on failure. Might be nil.
+	health *healthRegistry
 
 	reader sdkmetric.Reader
 	conn   net.Listener
 
-	srv              *http.Server
+	srv *http.Server
+	// time to wait for active requests on shutdown.
+	shutdownTimeout  time.Duration
 	finishServerChan <-chan struct{}
 }
 
-func parsePromhttpExporter(uri *url.URL) (*promhttpWrapper, error) {
+// probePaths are paths served by the metrics server, each could be overridden
+// with query param of the metrics address, named after the field, e.g.
+// http://0.0.0.0:9090?metrics=/internal/metrics&livez=/healthz
+type probePaths struct {
+	metrics  string
+	livez    string
+	readyz   string
+	startupz string
+}
+
+func defaultProbePaths() probePaths {
+	return probePaths{
+		metrics:  "/metrics",
+		livez:    "/healthz",
+		readyz:   "/readyz",
+		startupz: "/startupz",
+	}
+}
+
+func parseProbePaths(query url.Values) (probePaths, error) {
+	paths := defaultProbePaths()
+
+	for name, path := range map[string]*string{
+		"metrics":  &paths.metrics,
+		"livez":    &paths.livez,
+		"readyz":   &paths.readyz,
+		"startupz": &paths.startupz,
+	} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(v, "/") {
+			return probePaths{}, fmt.Errorf("%v path %q must be absolute", name, v)
+		}
+
+		*path = v
+	}
+
+	// mux panics on duplicates, so it's better to fail on parsing.
+	seen := make(map[string]struct{}, 4)
+	for _, path := range []string{paths.metrics, paths.livez, paths.readyz, paths.startupz} {
+		if _, ok := seen[path]; ok {
+			return probePaths{}, fmt.Errorf("path %q is used more than once", path)
+		}
+
+		seen[path] = struct{}{}
+	}
+
+	return paths, nil
+}
+
+// parseShutdownTimeout parses shutdown_timeout query param of the metrics
+// address, e.g. http://0.0.0.0:9090?shutdown_timeout=3s
+func parseShutdownTimeout(query url.Values) (time.Duration, error) {
+	v := query.Get("shutdown_timeout")
+	if v == "" {
+		return defaultServerStopTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid shutdown_timeout: %w", err)
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("shutdown_timeout must be positive, got %v", v)
+	}
+
+	return timeout, nil
+}
+
+func parsePromhttpExporter(uri *url.URL, ready func(context.Context) bool, health *healthRegistry) (*promhttpWrapper, error) {
+	if uri.Scheme != "http" && uri.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported metrics scheme %q", uri.Scheme)
+	}
+
 	host := uri.Hostname()
 	ipAddr := net.ParseIP(host)
 
@@ -58,6 +155,16 @@ func parsePromhttpExporter(uri *url.URL) (*promhttpWrapper, error) {
 
 	addr := &net.TCPAddr{IP: ipAddr, Port: portNum, Zone: ""}
 
+	paths, err := parseProbePaths(uri.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := parseShutdownTimeout(uri.Query())
+	if err != nil {
+		return nil, err
+	}
+
 	promreg := prometheus.NewRegistry()
 	prometheusExporter, err := otelprometheus.New(
 		otelprometheus.WithRegisterer(promreg),
@@ -69,29 +176,84 @@ func parsePromhttpExporter(uri *url.URL) (*promhttpWrapper, error) {
 	return &promhttpWrapper{
 		log:    nil, // will be initialized later
 		addr:   addr,
+		config: nil, // will be initialized later
+		useTLS: uri.Scheme == "https",
+
+		paths:    paths,
+		registry: promreg,
+		ready:    ready,
+		health:   health,
+
 		reader: prometheusExporter,
 		conn:   nil, // will be initialized later
 		srv: &http.Server{ //nolint:exhaustruct // server has a lot of fields
-			// handler is 404 by default.
-			Handler:           healthChecks(promreg, nil),
+			Handler:           nil, // will be initialized later
 			ReadTimeout:       defaultReadTimeout,
 			ReadHeaderTimeout: defaultReadHeaderTimeout,
 			WriteTimeout:      defaultWriteTimeout,
 			IdleTimeout:       defaultIdleTimeout,
 		},
+		shutdownTimeout:  shutdownTimeout,
+		finishServerChan: nil, // will be initialized later
 	}, nil
 }
 
-func (g *promhttpWrapper) configure(ctx context.Context, log LogCallbacks) error {
+func (g *promhttpWrapper) configure(ctx context.Context, log LogCallbacks, data *core.ConfigureData) error {
 	if g == nil {
 		return nil
 	}
 
+	if g.addr == nil {
+		return fmt.Errorf("metrics server: %w", core.ErrAddrNotSet)
+	}
+
 	g.log = log
+	g.srv.Handler = healthChecks(g.registry, g.isReady, g.health, g.paths)
+
+	if g.useTLS {
+		if len(data.AppCert.Certificate) == 0 {
+			return errors.New("https metrics endpoint requires application certificate")
+		}
+
+		g.config = &tls.Config{ //nolint:exhaustruct // config has a lot of fields
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{data.AppCert},
+		}
+	}
 
 	return nil
 }
 
+// AddReadyCheck adds readiness check, combined with the others with AND. Safe
+// to call at any time, even if metrics server is disabled.
+func (g *promhttpWrapper) AddReadyCheck(check func(context.Context) bool) {
+	if g == nil {
+		return
+	}
+
+	g.checksMu.Lock()
+	defer g.checksMu.Unlock()
+
+	g.checks = append(g.checks, check)
+}
+
+func (g *promhttpWrapper) isReady(ctx context.Context) bool {
+	if g.ready != nil && !g.ready(ctx) {
+		return false
+	}
+
+	g.checksMu.RLock()
+	defer g.checksMu.RUnlock()
+
+	for _, check := range g.checks {
+		if !check(ctx) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (g *promhttpWrapper) acquire(ctx context.Context) (err error) {
 	if g == nil {
 		return nil
@@ -110,8 +272,11 @@ func (g *promhttpWrapper) acquire(ctx context.Context) (err error) {
 
 	g.conn, err = listenConfig.Listen(ctx, g.addr.Network(), g.addr.String())
 	if err != nil {
-		// TODO: handle error correctly
-		return fmt.Errorf("listening on %q %q: %w", g.addr.Network(), g.addr.String(), err)
+		return core.ListenError(g.addr.Network(), g.addr.String(), err)
+	}
+
+	if g.config != nil {
+		g.conn = tls.NewListener(g.conn, g.config)
 	}
 
 	// calling metrics log here, cause address is already opened, and listener
@@ -143,7 +308,7 @@ func (g *promhttpWrapper) shutdown(ctx context.Context) (err error) {
 		return nil
 	}
 
-	timeoutCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+	timeoutCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), g.shutdownTimeout)
 	defer cancel()
 
 	if err = g.srv.Shutdown(timeoutCtx); err != nil {
@@ -165,13 +330,15 @@ func (g *promhttpWrapper) shutdown(ctx context.Context) (err error) {
 	return nil
 }
 
-func healthChecks(promRegister prometheus.Gatherer, ready func(context.Context) bool) http.Handler {
+// healthChecks builds the handler of the metrics server, anything outside of
+// paths is 404.
+func healthChecks(promRegister prometheus.Gatherer, ready func(context.Context) bool, health *healthRegistry, paths probePaths) http.Handler {
 	router := http.NewServeMux()
-	router.Handle("/healthz", healthz())
+	router.Handle(paths.livez, healthz())
 	// TODO
-	router.Handle("/readyz", readyz(ready))
-	router.Handle("/startupz", readyz(ready))
-	router.Handle("/metrics", promhttp.HandlerFor(promRegister, promhttp.HandlerOpts{
+	router.Handle(paths.readyz, readyz(ready, health))
+	router.Handle(paths.startupz, readyz(ready, health))
+	router.Handle(paths.metrics, promhttp.HandlerFor(promRegister, promhttp.HandlerOpts{
 		EnableOpenMetrics:                   true,
 		EnableOpenMetricsTextCreatedSamples: true,
 		ErrorLog:                            nil,
@@ -193,12 +360,19 @@ func healthz() http.Handler {
 	})
 }
 
-func readyz(ready func(context.Context) bool) http.Handler {
+// readyz reports not-ready if ready func fails or any health check fails,
+// listing failed components in the latter case.
+func readyz(ready func(context.Context) bool, health *healthRegistry) http.Handler {
 	if ready == nil {
 		ready = func(context.Context) bool { return true }
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing := health.failing(r.Context()); len(failing) > 0 {
+			http.Error(w, "failing components: "+strings.Join(failing, ", "), http.StatusServiceUnavailable)
+			return
+		}
+
 		if ready(r.Context()) {
 			w.WriteHeader(http.StatusOK)
 			return
@@ -0,0 +1,376 @@
+package runtime
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quenbyako/core"
+)
+
+// selfSignedCert generates certificate for 127.0.0.1, returning it with the
+// pool trusting it.
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "metrics"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+// startMetrics runs metrics server at addr, returning its actual address.
+func startMetrics(t *testing.T, addr string, data *core.ConfigureData) string {
+	t.Helper()
+
+	u, _ := url.Parse(addr)
+
+	srv, err := parsePromhttpExporter(u, nil, nil)
+	if err != nil {
+		t.Fatalf("parsing metrics address: %v", err)
+	}
+
+	if err := srv.configure(t.Context(), defaultLogs(slog.DiscardHandler), data); err != nil {
+		t.Fatalf("configuring metrics server: %v", err)
+	}
+
+	if err := srv.acquire(t.Context()); err != nil {
+		t.Fatalf("acquiring metrics server: %v", err)
+	}
+
+	t.Cleanup(func() { _ = srv.shutdown(t.Context()) })
+
+	return srv.conn.Addr().String()
+}
+
+// getStatus requests url, returning response status.
+func getStatus(t *testing.T, client *http.Client, url string) int {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("requesting %v: %v", url, err)
+	}
+	_ = resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestMetricsServer(t *testing.T) {
+	cert, pool := selfSignedCert(t)
+
+	t.Run("http", func(t *testing.T) {
+		addr := startMetrics(t, "http://127.0.0.1:0", &core.ConfigureData{})
+
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err != nil {
+			t.Fatalf("requesting metrics: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %v", resp.StatusCode)
+		}
+	})
+
+	t.Run("https", func(t *testing.T) {
+		addr := startMetrics(t, "https://127.0.0.1:0", &core.ConfigureData{AppCert: cert, Pool: pool})
+
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		}}
+
+		resp, err := client.Get("https://" + addr + "/metrics")
+		if err != nil {
+			t.Fatalf("requesting metrics: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status 200, got %v", resp.StatusCode)
+		}
+
+		if resp.TLS == nil {
+			t.Error("expected TLS connection")
+		}
+
+		// probes and scrapers are not required to present client certificate.
+		if got := getStatus(t, client, "https://"+addr+"/healthz"); got != http.StatusOK {
+			t.Errorf("expected status 200, got %v", got)
+		}
+	})
+
+	t.Run("https without certificate", func(t *testing.T) {
+		u, _ := url.Parse("https://127.0.0.1:0")
+
+		srv, err := parsePromhttpExporter(u, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := srv.configure(t.Context(), defaultLogs(slog.DiscardHandler), &core.ConfigureData{}); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		u, _ := url.Parse("tcp://127.0.0.1:9090")
+
+		if _, err := parsePromhttpExporter(u, nil, nil); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestMetricsPaths(t *testing.T) {
+	t.Run("custom", func(t *testing.T) {
+		addr := startMetrics(t, "http://127.0.0.1:0?metrics=/internal/metrics&livez=/livez", &core.ConfigureData{})
+
+		for path, want := range map[string]int{
+			"/internal/metrics": http.StatusOK,
+			"/livez":            http.StatusOK,
+			"/readyz":           http.StatusOK,
+			"/metrics":          http.StatusNotFound,
+			"/healthz":          http.StatusNotFound,
+		} {
+			if got := getStatus(t, http.DefaultClient, "http://"+addr+path); got != want {
+				t.Errorf("%v: expected status %v, got %v", path, want, got)
+			}
+		}
+	})
+
+	for _, addr := range []string{
+		"http://127.0.0.1:9090?metrics=internal",
+		"http://127.0.0.1:9090?metrics=/readyz",
+	} {
+		t.Run(addr, func(t *testing.T) {
+			u, _ := url.Parse(addr)
+
+			if _, err := parsePromhttpExporter(u, nil, nil); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestMetricsReadyChecks(t *testing.T) {
+	u, _ := url.Parse("http://127.0.0.1:0")
+
+	srv, err := parsePromhttpExporter(u, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var warm, connected atomic.Bool
+
+	srv.AddReadyCheck(func(context.Context) bool { return warm.Load() })
+	srv.AddReadyCheck(func(context.Context) bool { return connected.Load() })
+
+	if err := srv.configure(t.Context(), defaultLogs(slog.DiscardHandler), &core.ConfigureData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	probe := func() int {
+		rec := httptest.NewRecorder()
+		srv.srv.Handler.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/readyz", nil))
+
+		return rec.Code
+	}
+
+	if code := probe(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %v", code)
+	}
+
+	warm.Store(true)
+
+	if code := probe(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 until all checks pass, got %v", code)
+	}
+
+	connected.Store(true)
+
+	if code := probe(); code != http.StatusOK {
+		t.Errorf("expected status 200, got %v", code)
+	}
+
+	t.Run("app context", func(t *testing.T) {
+		app := &appCtx[core.UnimplementedActionConfig]{metricServer: srv}
+
+		if !core.AddReadyCheck[core.UnimplementedActionConfig](app, func(context.Context) bool { return false }) {
+			t.Fatal("expected readiness capability")
+		}
+
+		if code := probe(); code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %v", code)
+		}
+	})
+}
+
+func TestMetricsShutdownTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+
+	u, _ := url.Parse("http://127.0.0.1:0?shutdown_timeout=" + timeout.String())
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	// slow readiness probe keeps request active during shutdown.
+	srv, err := parsePromhttpExporter(u, func(context.Context) bool {
+		close(entered)
+		<-release
+
+		return true
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.configure(t.Context(), defaultLogs(slog.DiscardHandler), &core.ConfigureData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.acquire(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { _, _ = http.Get("http://" + srv.conn.Addr().String() + "/readyz") }() //nolint:noctx // released at cleanup
+	<-entered
+
+	start := time.Now()
+
+	if err := srv.shutdown(t.Context()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if elapsed := time.Since(start); elapsed < timeout || elapsed > defaultServerStopTimeout {
+		t.Errorf("expected shutdown to take %v, took %v", timeout, elapsed)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		u, _ := url.Parse("http://127.0.0.1:0?shutdown_timeout=-1s")
+		if _, err := parsePromhttpExporter(u, nil, nil); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestMetricsAddrNotSet(t *testing.T) {
+	err := (&promhttpWrapper{}).configure(t.Context(), defaultLogs(slog.DiscardHandler), &core.ConfigureData{})
+	if !errors.Is(err, core.ErrAddrNotSet) {
+		t.Errorf("expected %v, got %v", core.ErrAddrNotSet, err)
+	}
+
+	// nil server means metrics are disabled.
+	if err := (*promhttpWrapper)(nil).configure(t.Context(), defaultLogs(slog.DiscardHandler), &core.ConfigureData{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMetricsHealthChecks(t *testing.T) {
+	health := &healthRegistry{}
+	app := &appCtx[core.UnimplementedActionConfig]{health: health}
+
+	registry, ok := core.Health[core.UnimplementedActionConfig](app)
+	if !ok {
+		t.Fatal("expected health capability")
+	}
+
+	u, _ := url.Parse("http://127.0.0.1:0")
+
+	srv, err := parsePromhttpExporter(u, nil, health)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.configure(t.Context(), defaultLogs(slog.DiscardHandler), &core.ConfigureData{}); err != nil {
+		t.Fatal(err)
+	}
+
+	probe := func() (int, string) {
+		rec := httptest.NewRecorder()
+		srv.srv.Handler.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/readyz", nil))
+
+		return rec.Code, rec.Body.String()
+	}
+
+	var cacheErr, queueErr atomic.Pointer[error]
+	check := func(p *atomic.Pointer[error]) func(context.Context) error {
+		return func(context.Context) error {
+			if err := p.Load(); err != nil {
+				return *err
+			}
+
+			return nil
+		}
+	}
+
+	registry.RegisterHealthCheck("database", func(context.Context) error { return nil })
+	registry.RegisterHealthCheck("cache", check(&cacheErr))
+	registry.RegisterHealthCheck("queue", check(&queueErr))
+
+	if code, _ := probe(); code != http.StatusOK {
+		t.Errorf("expected status 200, got %v", code)
+	}
+
+	unavailable := errors.New("unavailable")
+	cacheErr.Store(&unavailable)
+	queueErr.Store(&unavailable)
+
+	code, body := probe()
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %v", code)
+	}
+
+	if want := "failing components: cache, queue\n"; body != want {
+		t.Errorf("expected body %q, got %q", want, body)
+	}
+
+	cacheErr.Store(nil)
+	queueErr.Store(nil)
+
+	if code, _ := probe(); code != http.StatusOK {
+		t.Errorf("expected status 200 after recovery, got %v", code)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
-
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/quenbyako/core"
 	"github.com/quenbyako/core/contrib/runtime/env"
@@ -21,8 +26,74 @@ import (
 
 const alternativeLib = false
 
-func Run[T core.ActionConfig](action core.ActionFunc[T]) func(context.Context, []string) core.ExitCode {
-	return func(ctx context.Context, _ []string) core.ExitCode {
+// DefaultShutdownTimeout bounds the shutdown phase of [Run] unless overridden
+// with [WithShutdownTimeout] or by config implementing [core.ShutdownConfig].
+const DefaultShutdownTimeout = 30 * time.Second
+
+type runParams struct {
+	shutdownTimeout time.Duration
+	drainSignal     os.Signal
+	// command line arguments of the current run, not an option.
+	args []string
+	// parses fields with "secret" tag option, skipped by env parsing, once
+	// secret engine is built. Not an option, nil for [RunContext].
+	parseSecrets func(ctx context.Context, config any, getSecret func(key string) ([]byte, error)) ([]core.EnvParam, error)
+}
+
+type RunOption func(*runParams)
+
+// WithShutdownTimeout sets the total time budget for shutting down acquired
+// params after the action returns. Params that do not finish in time are
+// reported as shutdown errors and abandoned. It takes precedence over timeout
+// of config implementing [core.ShutdownConfig].
+func WithShutdownTimeout(timeout time.Duration) RunOption {
+	return func(p *runParams) { p.shutdownTimeout = timeout }
+}
+
+// WithDrainSignal enables the drain phase, started when sig (e.g.
+// syscall.SIGUSR1) is received: readiness probe starts reporting not-ready and
+// every [core.Drainable] param is drained. Unlike shutdown, the context is not
+// cancelled and the action continues running.
+func WithDrainSignal(sig os.Signal) RunOption {
+	return func(p *runParams) { p.drainSignal = sig }
+}
+
+func Run[T core.ActionConfig](action core.ActionFunc[T], opts ...RunOption) func(context.Context, []string) core.ExitCode {
+	run := RunE(action, opts...)
+
+	return func(ctx context.Context, args []string) core.ExitCode {
+		err := run(ctx, args)
+		if err == nil {
+			return 0
+		}
+
+		exitErr := &ExitCodeError{Code: 1, Err: err}
+		errors.As(err, &exitErr)
+
+		if exitErr.Err != nil {
+			// errors are reported at the very end, when config is gone, but
+			// level of the config can't filter out errors anyway.
+			reportErrors(defaultLogs(defaultLogger(os.Stderr, slog.LevelInfo)), exitErr.Err)
+		}
+
+		return exitErr.Code
+	}
+}
+
+// RunE works like [Run], but returns failures instead of printing them and
+// mapping to exit code, so it could be embedded into larger programs: nil
+// means clean exit, otherwise error is [*ExitCodeError], joining
+// [*LifecycleError] of every failure (if any).
+func RunE[T core.ActionConfig](action core.ActionFunc[T], opts ...RunOption) func(context.Context, []string) error {
+	var p runParams
+	for _, o := range opts {
+		o(&p)
+	}
+
+	return func(ctx context.Context, args []string) error {
+		p := p
+		p.args = args
+
 		var config T
 
 		envRaw := os.Environ()
@@ -40,174 +111,584 @@ func Run[T core.ActionConfig](action core.ActionFunc[T]) func(context.Context, [
 		if alternativeLib {
 			err = env.Parse(ctx, &config, env.WithEnvironment(environ))
 		} else {
-			mappers := make(map[reflect.Type]envold.ParserFunc)
-			for typ, f := range internal.GetAllParseFunc() {
-				mappers[typ] = func(v string) (any, error) { return f(ctx, v) }
-			}
-
 			var opt envold.Options
-			opt, activeParams = envParams(environ, mappers)
+			opt, activeParams = envParams(ctx, environ)
+			// secret engine is configured by the config itself
+			opt.SkipSecrets = true
 
 			err = envold.ParseWithOptions(&config, opt)
-		}
+			if err != nil {
+				closeFiles(activeParams())
+			}
 
-		// warn: aggregate error is not returned by value, not by pointer
-		if e := new(envold.AggregateError); errors.As(err, e) {
-			var missedFields []string
+			p.parseSecrets = func(ctx context.Context, config any, getSecret func(string) ([]byte, error)) ([]core.EnvParam, error) {
+				opt, activeParams := envParams(ctx, environ)
+				opt.GetSecret = getSecret
 
-			for _, err := range e.Errors {
-				if e := new(envold.VarIsNotSetError); errors.As(err, e) {
-					missedFields = append(missedFields, e.Key)
-				} else {
-					panic(err)
-				}
-			}
+				if err := envold.ParseSecretsWithOptions(config, opt); err != nil {
+					closeFiles(activeParams())
 
-			slices.Sort(missedFields)
+					return nil, err
+				}
 
-			if len(missedFields) > 0 {
-				fmt.Fprintf(os.Stderr, "missing required environment variables: %v\n", missedFields)
-			} else {
-				panic("internal error: env.AggregateError without env.VarIsNotSetError")
+				return activeParams(), nil
 			}
+		}
 
-			return 1
-		} else if err != nil {
-			panic(err)
+		if err != nil {
+			return &ExitCodeError{Code: 1, Err: envError(err)}
+		}
+
+		effective, err := getEffectiveEnvironment(&config, environ)
+		if err != nil {
+			return &ExitCodeError{Code: 1, Err: phaseError(PhaseEnv, nil, err)}
 		}
 
 		logHandler := defaultLogger(os.Stderr, config.GetLogLevel())
-		var log LogCallbacks = defaultLogs(logHandler)
+		defaultLogs(logHandler).EffectiveEnvironment(effective)
 
-		log.EffectiveEnvironment(getEffectiveEnvironment(&config, environ))
+		code, runErrs, err := lifecycle(ctx, logHandler, config, activeParams(), action, p)
+		if err != nil {
+			runErrs = append([]error{err}, runErrs...)
+		}
 
-		var clientCert tls.Certificate
-		if certPath, keyPath := config.ClientCertPaths(); certPath != "" && keyPath != "" {
-			var err error
-			if clientCert, err = tls.LoadX509KeyPair(certPath, keyPath); err != nil {
-				panic(fmt.Errorf("loading client certificate: %w", err))
-			}
+		if code == 0 && len(runErrs) == 0 {
+			return nil
 		}
 
-		secretEngine, err := secrets.BuildSecretEngine(ctx, config.GetSecretDSNs())
-		if err != nil {
-			panic(fmt.Errorf("building secret engine: %w", err))
+		return &ExitCodeError{Code: code, Err: errors.Join(runErrs...)}
+	}
+}
+
+// RunContext runs the full lifecycle of the action (Configure, Acquire, action
+// itself, Shutdown) in-process, using provided config as is: environment is
+// not parsed and no signals are handled, so ctx is the only way to stop the
+// action. Params are collected from exported fields of config.
+//
+// Returned error reports setup failures, in which case action is not called
+// and exit code is 1. If ctx is done before params are acquired, nothing is
+// acquired and action is not called either, but exit code is 0.
+func RunContext[T core.ActionConfig](ctx context.Context, config T, action core.ActionFunc[T], opts ...RunOption) (core.ExitCode, error) {
+	var p runParams
+	for _, o := range opts {
+		o(&p)
+	}
+	// signals are process-wide, so they are left to Run only.
+	p.drainSignal = nil
+
+	logHandler := defaultLogger(os.Stderr, config.GetLogLevel())
+
+	code, runErrs, err := lifecycle(ctx, logHandler, config, collectParams(&config), action, p)
+	for _, err := range runErrs {
+		reportErrors(defaultLogs(logHandler), err)
+	}
+
+	return code, err
+}
+
+// lifecycle configures and acquires params, runs the action with servers and
+// shuts everything down. Setup errors are returned as error, while serving and
+// shutdown ones, happening after the action was called, are returned
+// separately and reflected in exit code.
+func lifecycle[T core.ActionConfig](
+	ctx context.Context,
+	logHandler slog.Handler,
+	config T,
+	configurations []core.EnvParam,
+	action core.ActionFunc[T],
+	p runParams,
+) (core.ExitCode, []error, error) {
+	var log LogCallbacks = defaultLogs(logHandler)
+	drain := &drainer{log: log}
+	health := &healthRegistry{}
+
+	// feature provider is registered globally, so released on any exit.
+	var flags *featureProvider
+
+	// files are opened by env parsing already, but params are shut down only
+	// once the action is started.
+	started := false
+	defer func() {
+		if started {
+			return
 		}
-		caCerts := loadCertificates(config.GetCertPaths())
-		version, _ := core.VersionFromContext(ctx)
-		pipes, _ := core.PipelinesFromContext(ctx)
 
-		opts := []observability.NewOption{
-			observability.WithLogLevel(config.GetLogLevel()),
-			observability.WithLogWriter(pipes.Stderr()),
+		closeFiles(configurations)
+
+		if err := flags.shutdown(); err != nil {
+			reportErrors(log, phaseError(PhaseShutdown, nil, fmt.Errorf("shutting down feature provider: %w", err)))
 		}
-		if u := config.GetTraceEndpoint(); u != nil {
-			opts = append(opts, observability.WithOtelAddr(u))
+	}()
+
+	if ctx.Err() != nil {
+		log.Cancelled(context.Cause(ctx))
+
+		return 0, nil, nil
+	}
+
+	if cfg, ok := any(config).(core.ValidatableConfig); ok {
+		if err := cfg.Validate(ctx); err != nil {
+			return 1, nil, validationError(err)
 		}
-		var metricServer *promhttpWrapper
-		if addr := config.GetMetricsAddr(); addr != nil {
-			metricServer, err = parsePromhttpExporter(addr)
+	}
+
+	var clientCert tls.Certificate
+	if certPath, keyPath := config.ClientCertPaths(); certPath != "" && keyPath != "" {
+		var err error
+		if clientCert, err = tls.LoadX509KeyPair(certPath, keyPath); err != nil {
+			return 1, nil, phaseError(PhaseSetup, nil, fmt.Errorf("loading client certificate: %w", err))
+		}
+	}
+
+	certPaths, err := rootPaths(config.GetCertPaths())
+	if err != nil {
+		return 1, nil, phaseError(PhaseSetup, nil, fmt.Errorf("loading CA certificates: %w", err))
+	}
+
+	caCerts, err := loadCertificates(os.DirFS("/"), certPaths)
+	if err != nil {
+		return 1, nil, phaseError(PhaseSetup, nil, fmt.Errorf("loading CA certificates: %w", err))
+	}
+
+	// default version is expected to be invalid in development builds.
+	version, explicit := core.VersionFromContext(ctx)
+	if explicit && !version.Valid() {
+		log.InvalidVersion(version)
+	}
+
+	appName, _ := core.AppNameFromContext(ctx)
+	pipes, _ := core.PipelinesFromContext(ctx)
+
+	features, flags, err := newFeatureClient(config, appName)
+	if err != nil {
+		return 1, nil, phaseError(PhaseSetup, nil, fmt.Errorf("setting up feature flags: %w", err))
+	}
+
+	opts := []observability.NewOption{
+		observability.WithLogLevel(config.GetLogLevel()),
+		observability.WithLogWriter(pipes.Stderr()),
+		observability.WithCertPool(caCerts),
+	}
+	if pipes.StderrIsTerminal() {
+		opts = append(opts, observability.WithLogFormat(observability.LogFormatText))
+	}
+	if u := config.GetTraceEndpoint(); u != nil {
+		opts = append(opts, observability.WithOtelAddr(u))
+	}
+	if cfg, ok := any(config).(core.EnvironmentConfig); ok {
+		opts = append(opts, observability.WithEnvironment(cfg.GetEnvironment()))
+	}
+	var metricServer *promhttpWrapper
+	if addr := config.GetMetricsAddr(); addr != nil {
+		metricServer, err = parsePromhttpExporter(addr, drain.ready, health)
+		if err != nil {
+			return 1, nil, phaseError(PhaseSetup, nil, fmt.Errorf("parsing metrics address %q: %w", addr, err))
+		}
+		opts = append(opts, observability.WithMetricReader(metricServer.reader))
+	}
+
+	newMetrics := observability.New
+	if config.GetTraceEndpoint() == nil && metricServer == nil {
+		// nothing to export, no need to construct sdk.
+		newMetrics = observability.NewNoop
+	}
+
+	m, err := newMetrics(ctx, opts...)
+	if err != nil {
+		return 1, nil, phaseError(PhaseSetup, nil, fmt.Errorf("setting up observability: %w", err))
+	}
+
+	secretOpts := []secrets.BuildOption{secrets.WithMeterProvider(m)}
+	if cfg, ok := any(config).(core.SecretsConfig); ok {
+		secretOpts = append(secretOpts, secrets.WithDSNFile(cfg.GetSecretDSNFile()))
+	}
+
+	secretEngine, err := secrets.BuildSecretEngine(ctx, config.GetSecretDSNs(), secretOpts...)
+	if err != nil {
+		return 1, nil, phaseError(PhaseSetup, nil, fmt.Errorf("building secret engine: %w", err))
+	}
+	ctx = core.WithSecrets(ctx, secretEngine)
+
+	// secret fields are the only ones set after validation, as secret engine
+	// depends on the config.
+	if p.parseSecrets != nil {
+		getSecret := func(key string) ([]byte, error) {
+			secret, err := secretEngine.GetSecret(ctx, key)
 			if err != nil {
-				panic(fmt.Errorf("parsing metrics address %q: %w", addr, err))
+				return nil, err
 			}
-			opts = append(opts, observability.WithMetricReader(metricServer.reader))
+
+			return secret.Get(ctx)
 		}
 
-		m, err := observability.New(ctx, opts...)
+		params, err := p.parseSecrets(ctx, &config, getSecret)
 		if err != nil {
-			panic(fmt.Errorf("setting up observability: %w", err))
+			return 1, nil, envError(err)
 		}
+		configurations = append(configurations, params...)
+	}
 
-		cfgData := core.ConfigureData{
-			AppCert: clientCert,
-			Pool:    caCerts,
-			Logger:  logHandler,
-			Secrets: secretEngine,
-			Version: version,
-			Metric:  m,
-			Trace:   m,
-		}
+	cfgData := core.ConfigureData{
+		AppCert:  clientCert,
+		Pool:     caCerts,
+		Logger:   logHandler,
+		Secrets:  secretEngine,
+		Version:  version,
+		Metric:   m,
+		Trace:    m,
+		Features: features,
+		Stdin:    pipes.Stdin(),
+		Stdout:   pipes.Stdout(),
+	}
 
-		// configuring
-		var configErrs []error
-		configurations := activeParams()
+	// metrics server has quite specific configuration, so separating it out
+	// of other params
+	configJobs := []func(context.Context) error{
+		func(ctx context.Context) error {
+			if err := metricServer.configure(ctx, log, &cfgData); err != nil {
+				return phaseError(PhaseConfigure, metricServer, fmt.Errorf("configuring metric server: %w", err))
+			}
 
-		// metrics server has quite specific configuration, so separating it out
-		// of other params
-		if err := metricServer.configure(ctx, log); err != nil {
-			configErrs = append(configErrs, fmt.Errorf("configuring metric server: %w", err))
-		}
-		for _, v := range configurations {
+			return nil
+		},
+	}
+	for _, v := range configurations {
+		configJobs = append(configJobs, func(ctx context.Context) error {
 			if err := v.Configure(ctx, &cfgData); err != nil {
-				configErrs = append(configErrs, err)
+				return phaseError(PhaseConfigure, v, err)
 			}
-		}
 
-		if len(configErrs) > 0 {
-			for _, err := range configErrs {
-				fmt.Fprintf(os.Stderr, "configuration error: %v\n", err)
+			return nil
+		})
+	}
+
+	if configErrs := runConcurrently(ctx, configJobs...); len(configErrs) > 0 {
+		return 1, nil, joinErrors("configuration error", configErrs)
+	}
+
+	// configuration could take a while, but until acquisition nothing is
+	// bound, so it's still fine to just leave.
+	if ctx.Err() != nil {
+		log.Cancelled(context.Cause(ctx))
+
+		return 0, nil, nil
+	}
+
+	acquireData := core.AcquireData{}
+
+	// each job writes only its own flag, they are read after all jobs are
+	// finished.
+	var (
+		metricsAcquired bool
+		acquired        = make([]bool, len(configurations))
+	)
+
+	acquireJobs := []func(context.Context) error{
+		func(ctx context.Context) error {
+			if err := metricServer.acquire(ctx); err != nil {
+				return phaseError(PhaseAcquire, metricServer, fmt.Errorf("acquiring metric server: %w", err))
+			}
+
+			metricsAcquired = true
+
+			return nil
+		},
+	}
+	for i, v := range configurations {
+		acquireJobs = append(acquireJobs, func(ctx context.Context) error {
+			if err := v.Acquire(ctx, &acquireData); err != nil {
+				return phaseError(PhaseAcquire, v, fmt.Errorf("acquiring %T: %w", v, err))
+			}
+
+			acquired[i] = true
+
+			return nil
+		})
+	}
+
+	acquireErrs := runConcurrently(ctx, acquireJobs...)
+	if len(acquireErrs) > 0 {
+		// releasing what is already bound, otherwise failed [RunContext]
+		// leaks listeners.
+		var (
+			bound        []core.EnvParam
+			boundMetrics *promhttpWrapper
+		)
+		for i, v := range configurations {
+			if acquired[i] {
+				bound = append(bound, v)
 			}
-			return 1
+		}
+		if metricsAcquired {
+			boundMetrics = metricServer
 		}
 
-		acquireData := core.AcquireData{}
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout(config, p.shutdownTimeout))
+		defer cancel()
 
-		var acquireErrs []error
+		shutdownErrs := shutdownParams(shutdownCtx, bound, boundMetrics)
 
-		if err := metricServer.acquire(ctx); err != nil {
-			acquireErrs = append(acquireErrs, fmt.Errorf("acquiring metric server: %w", err))
+		return 1, nil, errors.Join(joinErrors("acquiring resources", acquireErrs), errors.Join(shutdownErrs...))
+	}
+
+	var servables []core.Servable
+	for _, v := range configurations {
+		if s, ok := v.(core.Servable); ok {
+			servables = append(servables, s)
 		}
-		for _, v := range configurations {
-			if err := v.Acquire(ctx, &acquireData); err != nil {
-				acquireErrs = append(acquireErrs, fmt.Errorf("acquiring %T: %w", v, err))
-			}
+		if d, ok := v.(core.Drainable); ok {
+			drain.params = append(drain.params, d)
 		}
+	}
 
-		if len(acquireErrs) > 0 {
-			for _, err := range acquireErrs {
-				fmt.Fprintf(os.Stderr, "acquiring resources: %v\n", err)
-			}
+	log.StartupSummary(newStartupSummary(config, metricServer, features != nil, secretEngine, servables))
+
+	app := &appCtx[T]{
+		isPipeline: pipes.IsPipeline(),
+		args:       p.args,
+		stdin:      pipes.Stdin(),
+		stdout:     pipes.Stdout(),
+		log:        logHandler,
+		metric:     m,
+		trace:      m,
+		features:   features,
+		config:     config,
+		appName:    appName,
+		version:    version,
+
+		metricServer: metricServer,
+		health:       health,
+		ready:        newReadySignal(),
+	}
 
-			return 1
+	started = true
+	stopDrain := drain.watch(ctx, p.drainSignal)
+	code, serveErrs := serve(ctx, func(ctx context.Context) core.ExitCode {
+		// see [core.ReadAll]
+		app.stdin = ctxReader{ctx: ctx, r: app.stdin}
+
+		return action(ctx, app)
+	}, servables, app.ready)
+	stopDrain()
+
+	// action is finished, so the original context is most likely
+	// cancelled: detach from it, but bound the whole phase, so stuck
+	// params can't hang the process forever.
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout(config, p.shutdownTimeout))
+	defer cancel()
+
+	shutdownErrs := shutdownParams(shutdownCtx, configurations, metricServer)
+	if err := withDeadline(shutdownCtx, flags.shutdown); err != nil {
+		shutdownErrs = append(shutdownErrs, phaseError(PhaseShutdown, nil, fmt.Errorf("shutting down feature provider: %w", err)))
+	}
+	// flushing telemetry last, so spans and metrics of shutdown itself are
+	// not lost.
+	if telemetry, ok := m.(interface {
+		Shutdown(ctx context.Context) error
+	}); ok {
+		if err := withDeadline(shutdownCtx, func() error { return telemetry.Shutdown(shutdownCtx) }); err != nil {
+			shutdownErrs = append(shutdownErrs, phaseError(PhaseShutdown, nil, fmt.Errorf("flushing telemetry: %w", err)))
 		}
+	}
 
-		code := action(ctx, &appCtx[T]{
-			IsPipeline: pipes.IsPipeline(),
-			stdin:      pipes.Stdin(),
-			stdout:     pipes.Stdout(),
-			log:        logHandler,
-			metric:     m,
-			trace:      m,
-			config:     config,
-			version:    version,
-		})
+	// serving errors are already reflected in exit code.
+	if len(shutdownErrs) > 0 {
+		return 1, append(serveErrs, shutdownErrs...), nil
+	}
 
-		shutdownData := core.ShutdownData{}
+	return code, serveErrs, nil
+}
+
+// shutdownParams releases params in reverse acquisition order: metric server
+// is acquired first, so it's stopped last. metricServer is nil, if it's
+// disabled or not acquired.
+func shutdownParams(ctx context.Context, params []core.EnvParam, metricServer *promhttpWrapper) []error {
+	shutdownData := core.ShutdownData{}
 
-		var shutdownErrs []error
+	var errs []error
 
-		if err := metricServer.shutdown(ctx); err != nil {
-			shutdownErrs = append(shutdownErrs, fmt.Errorf("shutting down metric server: %w", err))
+	for _, v := range slices.Backward(params) {
+		if err := withDeadline(ctx, func() error { return v.Shutdown(ctx, &shutdownData) }); err != nil {
+			errs = append(errs, phaseError(PhaseShutdown, v, fmt.Errorf("shutting down %T: %w", v, err)))
 		}
-		for _, v := range configurations {
-			if err := v.Shutdown(ctx, &shutdownData); err != nil {
-				shutdownErrs = append(shutdownErrs, err)
+	}
+	if err := withDeadline(ctx, func() error { return metricServer.shutdown(ctx) }); err != nil {
+		errs = append(errs, phaseError(PhaseShutdown, metricServer, fmt.Errorf("shutting down metric server: %w", err)))
+	}
+
+	return errs
+}
+
+// shutdownTimeout returns explicitly set timeout, falling back to the one of
+// config, if it implements [core.ShutdownConfig], and then to
+// [DefaultShutdownTimeout].
+func shutdownTimeout(config any, explicit time.Duration) time.Duration {
+	if explicit > 0 {
+		return explicit
+	}
+
+	if cfg, ok := config.(core.ShutdownConfig); ok && cfg.GetShutdownTimeout() > 0 {
+		return cfg.GetShutdownTimeout()
+	}
+
+	return DefaultShutdownTimeout
+}
+
+// joinErrors prefixes each error, keeping one error per line when printed.
+func joinErrors(prefix string, errs []error) error {
+	wrapped := make([]error, len(errs))
+	for i, err := range errs {
+		wrapped[i] = fmt.Errorf("%v: %w", prefix, err)
+	}
+
+	return errors.Join(wrapped...)
+}
+
+// serve runs the action alongside all servable params. Servers start once
+// ready is fired, either by the action itself or by its zero return. The
+// action and the servers share a single cancellable context:
+//
+//   - returning non-zero exit code from the action cancels the servers, so
+//     they shut down gracefully;
+//   - returning zero keeps servers running until ctx is cancelled, so actions
+//     may only register handlers and return (if there are no servers, serve
+//     returns immediately);
+//   - a failing server cancels the action and all other servers;
+//   - cancelling ctx (e.g. on a signal) cancels everything.
+//
+// The returned exit code is the one returned by the action, unless it's zero
+// and any server has failed: then it's 1.
+func serve(ctx context.Context, action func(context.Context) core.ExitCode, servables []core.Servable, ready *readySignal) (core.ExitCode, []error) {
+	serveCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var code core.ExitCode
+
+	jobs := []func(context.Context) error{
+		func(ctx context.Context) error {
+			code = action(ctx)
+			if code != 0 || len(servables) == 0 {
+				stop()
+			} else {
+				ready.fire()
+			}
+
+			return nil
+		},
+	}
+	for _, s := range servables {
+		jobs = append(jobs, func(ctx context.Context) error {
+			select {
+			case <-ready.done:
+			case <-ctx.Done():
+				return nil
 			}
-		}
 
-		if len(shutdownErrs) > 0 {
-			for _, err := range shutdownErrs {
-				fmt.Fprintf(os.Stderr, "shutdown error: %v\n", err)
+			if err := s.Serve(ctx); err != nil {
+				return phaseError(PhaseServe, s, fmt.Errorf("serving %T: %w", s, err))
 			}
 
-			return 1
+			return nil
+		})
+	}
+
+	errs := runConcurrently(serveCtx, jobs...)
+	if len(errs) > 0 && code == 0 {
+		code = 1
+	}
+
+	return code, errs
+}
+
+// readySignal is fired once action reports readiness (see [core.Ready]) or
+// returns with zero exit code, so servers never start before action registers
+// its handlers.
+type readySignal struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func newReadySignal() *readySignal { return &readySignal{done: make(chan struct{})} }
+
+func (r *readySignal) fire() { r.once.Do(func() { close(r.done) }) }
+
+// runConcurrently executes jobs with [core.RunJobs], so the first failure
+// cancels the siblings, and returns the collected errors sorted by message to
+// keep reporting deterministic.
+func runConcurrently(ctx context.Context, jobs ...func(context.Context) error) []error {
+	err := core.RunJobs(ctx, jobs...)
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+	//nolint:errorlint // RunJobs returns result of errors.Join as is.
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+
+	return errs
+}
+
+// withDeadline runs f, but returns as soon as ctx is done, even if f ignores
+// the context and keeps running.
+func withDeadline(ctx context.Context, f func() error) error {
+	res := make(chan error, 1)
+	go func() { res <- f() }()
+
+	select {
+	case err := <-res:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fileParam closes file opened by env parser on shutdown.
+type fileParam struct {
+	file io.Closer
+}
+
+var _ core.EnvParam = (*fileParam)(nil)
+
+func (p *fileParam) Configure(context.Context, *core.ConfigureData) error { return nil }
+func (p *fileParam) Acquire(context.Context, *core.AcquireData) error     { return nil }
+
+func (p *fileParam) Shutdown(context.Context, *core.ShutdownData) error {
+	if err := p.file.Close(); err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+
+	return nil
+}
+
+// closeFiles closes files opened by env parser, which are not going to be shut
+// down, e.g. if parsing or setup failed.
+func closeFiles(params []core.EnvParam) {
+	for _, p := range params {
+		if f, ok := p.(*fileParam); ok {
+			_ = f.file.Close()
 		}
+	}
+}
+
+// contextParsers adapts registered parsers for envold, which is not aware of
+// context, binding every parser invocation to ctx of the current parse.
+func contextParsers(ctx context.Context) map[reflect.Type]envold.ParserFunc {
+	funcs := internal.GetAllParseFunc()
 
-		return code
+	mappers := make(map[reflect.Type]envold.ParserFunc, len(funcs))
+	for typ, f := range funcs {
+		mappers[typ] = func(v string) (any, error) { return f(ctx, v) }
 	}
+
+	return mappers
 }
 
-func envParams(e map[string]string, mappers map[reflect.Type]envold.ParserFunc) (envold.Options, func() []core.EnvParam) {
+// envParams returns options of envold, binding parsers to ctx.
+func envParams(ctx context.Context, e map[string]string) (envold.Options, func() []core.EnvParam) {
 	var activeParams []core.EnvParam
 
 	return envold.Options{
@@ -216,32 +697,125 @@ func envParams(e map[string]string, mappers map[reflect.Type]envold.ParserFunc)
 		DefaultValueTagName: "default",
 		RequiredIfNoDef:     true,
 		Environment:         e,
-		FuncMap:             mappers,
+		FuncMap:             contextParsers(ctx),
+		FuncMapWithLayout: func(layout string) map[reflect.Type]envold.ParserFunc {
+			return contextParsers(core.WithParseLayout(ctx, layout))
+		},
+		IsAbort: func(err error) bool { return errors.Is(err, env.ErrAbortParse) },
 		OnSet: func(tag string, value any, isDefault bool) {
-			if v, ok := value.(core.EnvParam); ok {
+			switch v := value.(type) {
+			case core.EnvParam:
 				activeParams = append(activeParams, v)
+			case *os.File:
+				if v != nil {
+					activeParams = append(activeParams, &fileParam{file: v})
+				}
+			case fs.File:
+				activeParams = append(activeParams, &fileParam{file: v})
 			}
 		},
 	}, func() []core.EnvParam { return activeParams }
 }
 
-func getEffectiveEnvironment(config any, e map[string]string) map[string]string {
-	opts, _ := envParams(nil, nil)
+func getEffectiveEnvironment(config any, e map[string]string) (map[string]string, error) {
+	// parsers aren't called, they only tell which pointers are values.
+	opts, _ := envParams(context.Background(), nil)
 	fields, err := envold.GetFieldParamsWithOptions(config, opts)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("collecting effective environment: %w", err)
 	}
 
 	params := make(map[string]string)
 	for _, field := range fields {
-		params[field.Key] = field.DefaultValue
+		// secret references may be inline values, pem may be a private key.
+		redacted := field.Sensitive || field.Secret || field.PEM
+
+		value, ok := e[field.Key]
+		if !ok {
+			value = field.DefaultValue
+		}
+		params[field.Key] = redact(value, redacted)
+
+		// indexed items are listed only if set.
+		for i := 0; field.Indexed; i++ {
+			k := field.Key + "_" + strconv.Itoa(i)
+			v, ok := e[k]
+			if !ok {
+				break
+			}
+			params[k] = redact(v, redacted)
+		}
 	}
 
-	for k, v := range e {
-		if _, ok := params[k]; ok {
-			params[k] = v
+	return params, nil
+}
+
+// redact replaces non-empty value with [env.Redacted], if redacted is set.
+func redact(value string, redacted bool) string {
+	if redacted && value != "" {
+		return env.Redacted
+	}
+
+	return value
+}
+
+// collectParams walks exported fields of config, returning every param found,
+// like env parsing does for values it sets.
+func collectParams(config any) []core.EnvParam {
+	var params []core.EnvParam
+
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct {
+			return
+		}
+
+		for i := range v.NumField() {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+
+			field := v.Field(i)
+			if param, ok := asParam(field); ok {
+				params = append(params, param)
+
+				continue
+			}
+
+			walk(field)
 		}
 	}
 
+	walk(reflect.ValueOf(config))
+
 	return params
 }
+
+func asParam(v reflect.Value) (core.EnvParam, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return nil, false
+		}
+	default:
+	}
+
+	if param, ok := v.Interface().(core.EnvParam); ok {
+		return param, true
+	}
+
+	if v.CanAddr() {
+		if param, ok := v.Addr().Interface().(core.EnvParam); ok {
+			return param, true
+		}
+	}
+
+	return nil, false
+}
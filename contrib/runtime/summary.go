@@ -0,0 +1,66 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/quenbyako/core"
+	"github.com/quenbyako/core/secrets"
+)
+
+// StartupSummary lists optional features enabled for the application,
+// reported with [LogCallbacks.StartupSummary] once all params are acquired.
+type StartupSummary struct {
+	// Metrics is set, if metrics server is enabled.
+	Metrics bool
+	// Traces is set, if trace endpoint is configured.
+	Traces bool
+	// Features is set, if feature flags provider is configured.
+	Features bool
+	// Secrets are sorted names of secret storages, empty if there are none.
+	Secrets []string
+	// Servers are descriptions of serving params: their String(), e.g.
+	// address, or their type, if they aren't [fmt.Stringer].
+	Servers []string
+}
+
+// storageLister is implemented by engines built with
+// [secrets.BuildSecretEngine].
+type storageLister interface {
+	Storages() []string
+}
+
+func newStartupSummary(
+	config core.ActionConfig,
+	metricServer *promhttpWrapper,
+	features bool,
+	secretEngine secrets.Engine,
+	servables []core.Servable,
+) StartupSummary {
+	s := StartupSummary{
+		Metrics:  metricServer != nil,
+		Traces:   config.GetTraceEndpoint() != nil,
+		Features: features,
+		Secrets:  []string{},
+		Servers:  make([]string, 0, len(servables)),
+	}
+
+	if l, ok := secretEngine.(storageLister); ok {
+		s.Secrets = l.Storages()
+	}
+
+	for _, srv := range servables {
+		s.Servers = append(s.Servers, describeServer(srv))
+	}
+
+	return s
+}
+
+// describeServer returns description of server, if it implements
+// [fmt.Stringer] (e.g. its address), or its type otherwise.
+func describeServer(srv core.Servable) string {
+	if s, ok := srv.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	return fmt.Sprintf("%T", srv)
+}
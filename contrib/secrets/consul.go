@@ -0,0 +1,194 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/quenbyako/core/secrets"
+)
+
+// ConsulStorage resolves secrets from HashiCorp Consul KV. Values are read on
+// each [secrets.Secret.Get] call, so dynamic config changes are picked up
+// without restart.
+type ConsulStorage struct {
+	http *http.Client
+	// created by engine itself, nil if provided with [WithConsulTransport].
+	ownTransport *http.Transport
+	addr         *url.URL
+	token        string
+	prefix       string
+}
+
+var _ secrets.Engine = (*ConsulStorage)(nil)
+
+type consulParams struct {
+	transport http.RoundTripper
+}
+
+type NewConsulOption func(*consulParams)
+
+// WithConsulTransport sets transport of requests to Consul. Transport is owned
+// by the caller, so closing the engine leaves its connections intact.
+func WithConsulTransport(transport http.RoundTripper) NewConsulOption {
+	return func(p *consulParams) { p.transport = transport }
+}
+
+// NewConsul connects to Consul agent addressed as
+// consul://host:port/kv/prefix. Secret keys are resolved relative to prefix.
+//
+// Supported query parameters:
+//   - dc: datacenter;
+//   - scheme: "http" (default) or "https".
+//
+// ACL token is read from CONSUL_HTTP_TOKEN environment variable, or from file
+// at CONSUL_HTTP_TOKEN_FILE, like Consul CLI does. It's never taken from the
+// address, since addresses are logged as is.
+func NewConsul(_ context.Context, u *url.URL, opts ...NewConsulOption) (secrets.Engine, error) {
+	if u == nil {
+		return nil, errors.New("no URL provided")
+	}
+
+	var p consulParams
+	for _, o := range opts {
+		o(&p)
+	}
+
+	// own transport, so closing the engine doesn't affect others.
+	var ownTransport *http.Transport
+	if p.transport == nil {
+		ownTransport = http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // always true for stdlib
+		p.transport = ownTransport
+	}
+
+	prefix, ok := strings.CutPrefix(path.Clean("/"+u.Path), "/kv")
+	if !ok || (prefix != "" && prefix[0] != '/') {
+		return nil, errors.New("consul address must point to kv path, e.g. consul://host/kv/path")
+	}
+
+	query := u.Query()
+	if query.Has("token") {
+		return nil, errors.New("consul token must not be set in address, use CONSUL_HTTP_TOKEN or CONSUL_HTTP_TOKEN_FILE")
+	}
+
+	token, err := consulToken()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := query.Get("scheme")
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	addr := &url.URL{Scheme: scheme, Host: u.Host, Path: "/v1/kv/"}
+	if dc := query.Get("dc"); dc != "" {
+		addr.RawQuery = url.Values{"dc": {dc}}.Encode()
+	}
+
+	return &ConsulStorage{
+		http:         &http.Client{Transport: p.transport},
+		ownTransport: ownTransport,
+		addr:         addr,
+		token:        token,
+		prefix:       strings.TrimPrefix(prefix, "/"),
+	}, nil
+}
+
+// consulToken returns ACL token from the environment, preferring explicit
+// token over the token file.
+func consulToken() (string, error) {
+	if token := os.Getenv("CONSUL_HTTP_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	file := os.Getenv("CONSUL_HTTP_TOKEN_FILE")
+	if file == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("reading consul token file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (c *ConsulStorage) GetSecret(ctx context.Context, key string) (secrets.Secret, error) {
+	s := &consulSecret{
+		storage: c,
+		key:     path.Join(c.prefix, key),
+	}
+
+	if _, err := s.Get(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (c *ConsulStorage) Close() error {
+	if c.ownTransport != nil {
+		c.ownTransport.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+type consulSecret struct {
+	storage *ConsulStorage
+	key     string
+}
+
+var _ secrets.Secret = (*consulSecret)(nil)
+
+// Get implements Secret.
+func (c *consulSecret) Get(ctx context.Context) ([]byte, error) {
+	u := c.storage.addr.JoinPath(c.key)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	if c.storage.token != "" {
+		req.Header.Set("X-Consul-Token", c.storage.token)
+	}
+
+	resp, err := c.storage.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("reading key %q: %w", c.key, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, secrets.ErrSecretNotFound
+	case http.StatusForbidden:
+		return nil, secrets.ErrPermissionDenied
+	default:
+		return nil, fmt.Errorf("reading key %q: unexpected status %v", c.key, resp.Status)
+	}
+
+	// values are base64 encoded, json decodes them into []byte as is.
+	var pairs []struct {
+		Value []byte
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		return nil, fmt.Errorf("decoding key %q: %w", c.key, err)
+	}
+
+	if len(pairs) == 0 {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	return pairs[0].Value, nil
+}
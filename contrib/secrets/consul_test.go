@@ -0,0 +1,165 @@
+package secrets_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/quenbyako/core/contrib/secrets"
+	"github.com/quenbyako/core/secrets"
+)
+
+// consulKV mocks Consul HTTP API, serving KV reads from the map.
+type consulKV struct {
+	values   map[string]string
+	requests []*http.Request
+	// number of CloseIdleConnections calls.
+	closed int
+}
+
+func (c *consulKV) CloseIdleConnections() { c.closed++ }
+
+func (c *consulKV) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+
+	key, ok := strings.CutPrefix(req.URL.Path, "/v1/kv/")
+	if !ok || req.Method != http.MethodGet {
+		return nil, fmt.Errorf("unexpected request %v %v", req.Method, req.URL)
+	}
+
+	value, ok := c.values[key]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+	}
+
+	body := fmt.Sprintf(`[{"Key":%q,"Value":%q}]`, key, base64.StdEncoding.EncodeToString([]byte(value)))
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestConsul(t *testing.T) {
+	kv := &consulKV{values: map[string]string{"app/config/db_password": "hunter2"}}
+
+	t.Setenv("CONSUL_HTTP_TOKEN", "acl-token")
+
+	u, _ := url.Parse("consul://consul.local:8500/kv/app/config?dc=eu1&scheme=https")
+
+	engine, err := NewConsul(t.Context(), u, WithConsulTransport(kv))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = engine.Close() })
+
+	t.Run("present key", func(t *testing.T) {
+		secret, err := engine.GetSecret(t.Context(), "db_password")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := secret.Get(t.Context())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(data) != "hunter2" {
+			t.Fatalf("expected %q, got %q", "hunter2", data)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		if _, err := engine.GetSecret(t.Context(), "unknown"); !errors.Is(err, secrets.ErrSecretNotFound) {
+			t.Fatalf("expected %v, got %v", secrets.ErrSecretNotFound, err)
+		}
+	})
+
+	t.Run("connection config", func(t *testing.T) {
+		if len(kv.requests) == 0 {
+			t.Fatal("no requests were made")
+		}
+
+		req := kv.requests[0]
+		if req.URL.Scheme != "https" || req.URL.Host != "consul.local:8500" {
+			t.Errorf("unexpected consul address %v", req.URL)
+		}
+
+		if got := req.Header.Get("X-Consul-Token"); got != "acl-token" {
+			t.Errorf("expected token %q, got %q", "acl-token", got)
+		}
+
+		if got := req.URL.Query().Get("dc"); got != "eu1" {
+			t.Errorf("expected datacenter %q, got %q", "eu1", got)
+		}
+	})
+
+	t.Run("token file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token")
+		if err := os.WriteFile(path, []byte("file-token\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("CONSUL_HTTP_TOKEN", "")
+		t.Setenv("CONSUL_HTTP_TOKEN_FILE", path)
+
+		kv := &consulKV{values: map[string]string{"app/config/db_password": "hunter2"}}
+
+		engine, err := NewConsul(t.Context(), u, WithConsulTransport(kv))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := engine.GetSecret(t.Context(), "db_password"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := kv.requests[0].Header.Get("X-Consul-Token"); got != "file-token" {
+			t.Errorf("expected token %q, got %q", "file-token", got)
+		}
+
+		t.Setenv("CONSUL_HTTP_TOKEN_FILE", filepath.Join(t.TempDir(), "missing"))
+		if _, err := NewConsul(t.Context(), u, WithConsulTransport(kv)); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+		}
+	})
+
+	t.Run("token in address", func(t *testing.T) {
+		u, _ := url.Parse("consul://consul.local:8500/kv/app/config?token=acl-token")
+		if _, err := NewConsul(t.Context(), u, WithConsulTransport(kv)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("close keeps provided transport", func(t *testing.T) {
+		engine, err := NewConsul(t.Context(), u, WithConsulTransport(kv))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := engine.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if kv.closed != 0 {
+			t.Errorf("provided transport must not be closed, closed %v times", kv.closed)
+		}
+	})
+
+	t.Run("non kv path", func(t *testing.T) {
+		u, _ := url.Parse("consul://consul.local:8500/catalog")
+		if _, err := NewConsul(t.Context(), u); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
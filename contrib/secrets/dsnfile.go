@@ -0,0 +1,58 @@
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// mergeDSNFile returns copy of dsns, with missing or nil DSNs taken from the
+// file at path, see [WithDSNFile].
+func mergeDSNFile(dsns map[string]*url.URL, path string) (map[string]*url.URL, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening DSN file: %w", err)
+	}
+	defer file.Close()
+
+	res := maps.Clone(dsns)
+	if res == nil {
+		res = make(map[string]*url.URL)
+	}
+
+	// dotenv parsers are not used here, since storage names, unlike env
+	// variables, usually contain dashes.
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%v:%v: expected name=DSN line", path, lineNum)
+		}
+
+		name = strings.TrimSpace(name)
+		if res[name] != nil {
+			continue
+		}
+
+		dsn, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%v:%v: parsing DSN %q: %w", path, lineNum, name, err)
+		}
+
+		res[name] = dsn
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading DSN file: %w", err)
+	}
+
+	return res, nil
+}
@@ -10,11 +10,21 @@ import (
 
 type FileStorage struct {
 	secrets map[string]string
+	trim    bool
 }
 
 var _ secrets.Engine = (*FileStorage)(nil)
 
-func NewFile(path string) (secrets.Engine, error) {
+type FileOption func(*FileStorage)
+
+// WithFileTrim controls whether trailing whitespace is trimmed from secret
+// values, see [secrets.NewTrimmedSecret]. Disabled by default. In DSN it's set
+// with "trim" query parameter: "file://path?trim=true".
+func WithFileTrim(enabled bool) FileOption {
+	return func(c *FileStorage) { c.trim = enabled }
+}
+
+func NewFile(path string, opts ...FileOption) (secrets.Engine, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -26,9 +36,14 @@ func NewFile(path string) (secrets.Engine, error) {
 		return nil, err
 	}
 
-	return &FileStorage{
+	storage := &FileStorage{
 		secrets: envs,
-	}, nil
+	}
+	for _, o := range opts {
+		o(storage)
+	}
+
+	return storage, nil
 }
 
 func (c *FileStorage) GetSecret(_ context.Context, key string) (secrets.Secret, error) {
@@ -37,6 +52,10 @@ func (c *FileStorage) GetSecret(_ context.Context, key string) (secrets.Secret,
 		return nil, secrets.ErrSecretNotFound
 	}
 
+	if c.trim {
+		return secrets.NewTrimmedSecret(secrets.NewPlainSecret([]byte(secret))), nil
+	}
+
 	return secrets.NewPlainSecret([]byte(secret)), nil
 }
 
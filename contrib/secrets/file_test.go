@@ -0,0 +1,57 @@
+package secrets_test
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/quenbyako/core/contrib/secrets"
+	"github.com/quenbyako/core/secrets"
+)
+
+func TestFileTrim(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	if err := os.WriteFile(path, []byte("WITH_NEWLINE=\"hunter2\\n\"\nPLAIN=hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		query string
+		key   string
+		want  string
+	}{
+		{query: "", key: "WITH_NEWLINE", want: "hunter2\n"},
+		{query: "trim=false", key: "WITH_NEWLINE", want: "hunter2\n"},
+		{query: "trim=true", key: "WITH_NEWLINE", want: "hunter2"},
+		{query: "trim=true", key: "PLAIN", want: "hunter2"},
+	} {
+		t.Run(tt.query+"/"+tt.key, func(t *testing.T) {
+			engine, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+				"file": {Scheme: "file", Path: path, RawQuery: tt.query},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = engine.Close() })
+
+			value, err := secrets.GetSecretString(t.Context(), engine, "file:"+tt.key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if value != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, value)
+			}
+		})
+	}
+
+	t.Run("invalid trim", func(t *testing.T) {
+		_, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+			"file": {Scheme: "file", Path: path, RawQuery: "trim=maybe"},
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -5,43 +5,171 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/url"
+	"slices"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/quenbyako/core/secrets"
 	"github.com/vincent-petithory/dataurl"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
 )
 
+// fetchLatencyBuckets are histogram boundaries (in seconds) covering local
+// lookups as well as slow network backends.
+//
+//nolint:gochecknoglobals // constant slice
+var fetchLatencyBuckets = []float64{
+	0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// buildStorage creates storage of DSN, replaced in tests.
+//
+//nolint:gochecknoglobals // test seam
+var buildStorage = newSecretStorage
+
+// ErrInlineDataDisabled is returned by the engine built with
+// [BuildSecretEngine] for inline "data:" addresses when they are disabled via
+// [WithInlineData].
+var ErrInlineDataDisabled = errors.New("inline data secrets are disabled")
+
 type multiEngine struct {
 	closed atomic.Bool
 
 	storages map[string]secrets.Engine
+	// backend schemes of storages by their names, labeling fetch latency.
+	schemes map[string]string
+	// fallback resolves addresses of unknown storages, if set.
+	fallback     secrets.Engine
+	inlineData   bool
+	fetchLatency metric.Float64Histogram
+}
+
+type buildParams struct {
+	inlineData    bool
+	meterProvider metric.MeterProvider
+	dsnFile       string
+}
+
+type BuildOption func(*buildParams)
+
+// WithInlineData controls whether addresses starting with "data:" are decoded
+// in place as RFC 2397 data URLs. It is enabled by default for compatibility.
+//
+// SECURITY: with inline data enabled, anyone who can set a secret address
+// (e.g. through an environment variable) can supply the secret value itself,
+// bypassing the configured storages entirely. Applications that expect all
+// secrets to come from a trusted backend should disable it.
+func WithInlineData(enabled bool) BuildOption {
+	return func(p *buildParams) { p.inlineData = enabled }
+}
+
+// WithMeterProvider enables recording "secrets.fetch.duration" histogram of
+// every secret lookup, labeled by outcome and "scheme" of the storage backend
+// (DSN scheme, not storage name), so storages sharing a backend share labels.
+func WithMeterProvider(provider metric.MeterProvider) BuildOption {
+	return func(p *buildParams) { p.meterProvider = provider }
+}
+
+// WithDSNFile augments DSNs passed to [BuildSecretEngine] with ones listed in
+// file at path as "name=DSN" lines, lines starting with "#" are skipped. DSNs
+// passed directly take precedence: file only fills storages missing or having
+// nil DSN.
+func WithDSNFile(path string) BuildOption {
+	return func(p *buildParams) { p.dsnFile = path }
 }
 
-func BuildSecretEngine(ctx context.Context, u map[string]*url.URL) (secrets.Engine, error) {
+// BuildSecretEngine builds engine routing secret addresses "name:key" to the
+// storage named in u. Storage backend is chosen by the DSN scheme, not by the
+// name, so several storages may use the same backend:
+//
+//	BuildSecretEngine(ctx, map[string]*url.URL{
+//	    "vault-primary": {Scheme: "vault", Host: "vault-1:8200"},
+//	    "vault-backup":  {Scheme: "vault", Host: "vault-2:8200"},
+//	})
+//
+// Names must be valid lowercase URL schemes, since they are parsed as such
+// from addresses. Address fragment is a JSON Pointer selecting single field of
+// JSON secret, see [secrets.NewJSONFieldSecret], fragment without leading "/"
+// names top-level field:
+//
+//	vault:secret/data/db#/data/password
+//	file:DB#password
+//
+// The same applies to fragment of "data" DSN, decoding constant JSON value.
+//
+// Without any storages, every address except inline data resolves to an empty
+// secret, see [secrets.NewNoopEngine].
+func BuildSecretEngine(ctx context.Context, u map[string]*url.URL, opts ...BuildOption) (secrets.Engine, error) {
+	p := buildParams{
+		inlineData:    true,
+		meterProvider: noop.NewMeterProvider(),
+	}
+	for _, o := range opts {
+		o(&p)
+	}
+
+	fetchLatency, err := p.meterProvider.Meter("github.com/quenbyako/core/contrib/secrets").Float64Histogram(
+		"secrets.fetch.duration",
+		metric.WithDescription("Duration of secret lookups."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(fetchLatencyBuckets...),
+	)
+	if err != nil {
+		return &multiEngine{}, fmt.Errorf("creating fetch latency histogram: %w", err)
+	}
+
+	if p.dsnFile != "" {
+		if u, err = mergeDSNFile(u, p.dsnFile); err != nil {
+			return &multiEngine{}, err
+		}
+	}
+
 	if len(u) == 0 {
-		return &multiEngine{}, nil
+		return &multiEngine{fallback: secrets.NewNoopEngine(), inlineData: p.inlineData, fetchLatency: fetchLatency}, nil
 	}
 
 	storages := make(map[string]secrets.Engine, len(u))
-	for scheme, url := range u {
-		if url == nil {
+	schemes := make(map[string]string, len(u))
+	// sorted, so failures are reproducible.
+	for _, name := range slices.Sorted(maps.Keys(u)) {
+		dsn := u[name]
+		if parsed, err := url.Parse(name + ":"); err != nil || parsed.Scheme != name {
+			err = fmt.Errorf("invalid storage name %q: must be a lowercase URL scheme", name)
+
+			return &multiEngine{}, errors.Join(err, closeStorages(storages))
+		}
+
+		if dsn == nil {
 			// urls MIGHT be nil, cause user doesn't call them each time.
 			//
 			// However, we should throw an error that we know about this type,
 			// but user just didn't provide it.
-			storages[scheme] = secrets.NewUnsetStorage(scheme)
+			storages[name] = secrets.NewUnsetStorage(name)
+			schemes[name] = name
 			continue
 		}
 
-		storage, err := newSecretStorage(ctx, url)
+		storage, err := buildStorage(ctx, dsn)
 		if err != nil {
-			return &multiEngine{}, fmt.Errorf("creating storage for scheme %q: %w", scheme, err)
+			// storages created so far are not returned, so releasing them.
+			err = fmt.Errorf("creating storage %q: %w", name, err)
+
+			return &multiEngine{}, errors.Join(err, closeStorages(storages))
 		}
-		storages[scheme] = storage
+		storages[name] = storage
+		schemes[name] = dsn.Scheme
 	}
-	return &multiEngine{storages: storages}, nil
+	return &multiEngine{storages: storages, schemes: schemes, inlineData: p.inlineData, fetchLatency: fetchLatency}, nil
+}
+
+// Storages returns sorted names of configured storages.
+func (e *multiEngine) Storages() []string {
+	return slices.Sorted(maps.Keys(e.storages))
 }
 
 func (e *multiEngine) GetSecret(ctx context.Context, addr string) (secrets.Secret, error) {
@@ -53,45 +181,91 @@ func (e *multiEngine) GetSecret(ctx context.Context, addr string) (secrets.Secre
 		return secrets.NewEmptySecret(), nil
 	}
 
+	start := time.Now()
+
+	scheme, secret, err := e.getSecret(ctx, addr)
+	if scheme != "" {
+		outcome := "success"
+		if err != nil {
+			outcome = "error"
+		}
+
+		e.fetchLatency.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("scheme", scheme),
+			attribute.String("outcome", outcome),
+		))
+	}
+
+	return secret, err
+}
+
+// getSecret resolves addr, returning backend scheme of the storage it was
+// resolved with, or empty scheme, if addr can't be routed at all.
+func (e *multiEngine) getSecret(ctx context.Context, addr string) (string, secrets.Secret, error) {
 	// data is not correct url scheme, cause usually we are using data:// or
 	// something like this.
 	//
 	// Still, we had to check it in that way.
 	if strings.HasPrefix(addr, "data:") {
+		if !e.inlineData {
+			return "", nil, ErrInlineDataDisabled
+		}
+
 		data, err := dataurl.DecodeString(addr)
 		if err != nil {
-			return nil, fmt.Errorf("decoding data URL: %w", err)
+			return "data", nil, fmt.Errorf("decoding data URL: %w", err)
 		}
 
-		return secrets.NewPlainSecret(data.Data), nil
+		return "data", secrets.NewPlainSecret(data.Data), nil
 	}
 
 	key, err := url.Parse(addr)
 	if err != nil {
 
-		return nil, fmt.Errorf("parsing secret URL %q: %w", addr, err)
+		return "", nil, fmt.Errorf("parsing secret URL %q: %w", addr, err)
 	}
 
+	// scheme of the address is the storage name.
 	storage, ok := e.storages[key.Scheme]
+	if !ok && e.fallback != nil {
+		storage, ok = e.fallback, true
+	}
+
 	if !ok {
-		return nil, fmt.Errorf("no storage for scheme %q", key.Scheme)
+		return "", nil, fmt.Errorf("no storage named %q", key.Scheme)
+	}
+
+	// fallback has no DSN, so it's labeled with the address scheme.
+	scheme, ok := e.schemes[key.Scheme]
+	if !ok {
+		scheme = key.Scheme
 	}
 
 	secret, err := storage.GetSecret(ctx, key.Opaque)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secret from storage: %w", err)
+		return scheme, nil, fmt.Errorf("failed to get secret from storage: %w", err)
+	}
+
+	// fragment selects field of JSON secret, e.g. "vault:secret/data/db#/data/password".
+	if key.Fragment != "" {
+		secret = secrets.NewJSONFieldSecret(secret, fieldPointer(key.Fragment))
 	}
 
-	return secret, nil
+	return scheme, secret, nil
 }
 
 func (e *multiEngine) Close() error {
-	if e.closed.CompareAndSwap(false, true) {
+	// only the first call closes storages.
+	if !e.closed.CompareAndSwap(false, true) {
 		return nil
 	}
 
+	return closeStorages(e.storages)
+}
+
+func closeStorages(storages map[string]secrets.Engine) error {
 	var errs []error
-	for _, s := range e.storages {
+	for _, s := range storages {
 		if err := s.Close(); err != nil {
 			errs = append(errs, err)
 		}
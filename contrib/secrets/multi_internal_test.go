@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/quenbyako/core/secrets"
+)
+
+// countingEngine counts Close calls, failing them with err.
+type countingEngine struct {
+	closed int
+	err    error
+}
+
+func (e *countingEngine) GetSecret(context.Context, string) (secrets.Secret, error) {
+	return secrets.NewEmptySecret(), nil
+}
+
+func (e *countingEngine) Close() error {
+	e.closed++
+
+	return e.err
+}
+
+func TestMultiEngineClose(t *testing.T) {
+	errClose := errors.New("close failure")
+
+	primary, backup := &countingEngine{}, &countingEngine{err: errClose}
+	engine := &multiEngine{storages: map[string]secrets.Engine{"primary": primary, "backup": backup}}
+
+	if err := engine.Close(); !errors.Is(err, errClose) {
+		t.Fatalf("expected %v, got %v", errClose, err)
+	}
+
+	if err := engine.Close(); err != nil {
+		t.Fatalf("expected repeated close to be no-op, got %v", err)
+	}
+
+	for name, storage := range map[string]*countingEngine{"primary": primary, "backup": backup} {
+		if storage.closed != 1 {
+			t.Errorf("%v: expected single close, got %v", name, storage.closed)
+		}
+	}
+}
+
+func TestBuildSecretEngineClosesOnFailure(t *testing.T) {
+	errBuild := errors.New("build failure")
+
+	var created []*countingEngine
+	buildStorage = func(_ context.Context, u *url.URL) (secrets.Engine, error) {
+		if u.Scheme == "bad" {
+			return nil, errBuild
+		}
+
+		e := &countingEngine{}
+		created = append(created, e)
+
+		return e, nil
+	}
+	t.Cleanup(func() { buildStorage = newSecretStorage })
+
+	// storages are built in order of names.
+	_, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+		"a-primary": {Scheme: "ok"},
+		"b-backup":  {Scheme: "ok"},
+		"c-broken":  {Scheme: "bad"},
+		"d-unused":  {Scheme: "ok"},
+	})
+	if !errors.Is(err, errBuild) {
+		t.Fatalf("expected %v, got %v", errBuild, err)
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("expected storages before failed one to be created, got %v", len(created))
+	}
+
+	for i, storage := range created {
+		if storage.closed != 1 {
+			t.Errorf("storage %v: expected single close, got %v", i, storage.closed)
+		}
+	}
+}
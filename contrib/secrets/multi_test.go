@@ -0,0 +1,331 @@
+package secrets_test
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/quenbyako/core/contrib/secrets"
+	"github.com/quenbyako/core/secrets"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestInlineData(t *testing.T) {
+	const addr = "data:,hello"
+
+	t.Run("enabled by default", func(t *testing.T) {
+		engine, err := BuildSecretEngine(t.Context(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		secret, err := engine.GetSecret(t.Context(), addr)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := secret.Get(t.Context())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(data) != "hello" {
+			t.Fatalf("expected %q, got %q", "hello", data)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		engine, err := BuildSecretEngine(t.Context(), nil, WithInlineData(false))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := engine.GetSecret(t.Context(), addr); !errors.Is(err, ErrInlineDataDisabled) {
+			t.Fatalf("expected %v, got %v", ErrInlineDataDisabled, err)
+		}
+	})
+}
+
+func TestNoStorages(t *testing.T) {
+	engine, err := BuildSecretEngine(t.Context(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := engine.GetSecret(t.Context(), "vault:db/password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data, err := secret.Get(t.Context()); err != nil || data != nil {
+		t.Fatalf("expected empty secret, got %q, %v", data, err)
+	}
+}
+
+func TestFetchLatency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	if err := os.WriteFile(path, []byte("DB_PASSWORD=hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	engine, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+		"file": {Scheme: "file", Path: path},
+		// labeled by backend, not by storage name.
+		"file-backup": {Scheme: "file", Path: path},
+	}, WithMeterProvider(provider))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := engine.GetSecret(t.Context(), "file:DB_PASSWORD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := engine.GetSecret(t.Context(), "file-backup:DB_PASSWORD"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := engine.GetSecret(t.Context(), "file:MISSING"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := engine.GetSecret(t.Context(), "data:,inline"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// unroutable addresses have no scheme to label with.
+	if _, err := engine.GetSecret(t.Context(), "vault:unknown"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(t.Context(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rm.ScopeMetrics) != 1 || len(rm.ScopeMetrics[0].Metrics) != 1 {
+		t.Fatalf("expected single metric, got %+v", rm.ScopeMetrics)
+	}
+
+	m := rm.ScopeMetrics[0].Metrics[0]
+	if m.Name != "secrets.fetch.duration" {
+		t.Errorf("unexpected metric %q", m.Name)
+	}
+
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("expected float histogram, got %T", m.Data)
+	}
+
+	got := make(map[attribute.Distinct]uint64)
+	for _, p := range hist.DataPoints {
+		got[p.Attributes.Equivalent()] = p.Count
+	}
+
+	for want, count := range map[attribute.Set]uint64{
+		attribute.NewSet(attribute.String("scheme", "file"), attribute.String("outcome", "success")): 2,
+		attribute.NewSet(attribute.String("scheme", "file"), attribute.String("outcome", "error")):   1,
+		attribute.NewSet(attribute.String("scheme", "data"), attribute.String("outcome", "success")): 1,
+	} {
+		if got[want.Equivalent()] != count {
+			t.Errorf("expected %v fetches recorded for %v, got %v", count, want.Encoded(attribute.DefaultEncoder()), got[want.Equivalent()])
+		}
+	}
+
+	if len(hist.DataPoints) != 3 {
+		t.Errorf("expected 3 data points, got %v", len(hist.DataPoints))
+	}
+}
+
+func TestNamedStorages(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"primary.env": "DB_PASSWORD=primary\n",
+		"backup.env":  "DB_PASSWORD=backup\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+		"file-primary": {Scheme: "file", Path: filepath.Join(dir, "primary.env")},
+		"file-backup":  {Scheme: "file", Path: filepath.Join(dir, "backup.env")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = engine.Close() })
+
+	for addr, want := range map[string]string{
+		"file-primary:DB_PASSWORD": "primary",
+		"file-backup:DB_PASSWORD":  "backup",
+	} {
+		secret, err := engine.GetSecret(t.Context(), addr)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", addr, err)
+		}
+
+		data, err := secret.Get(t.Context())
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", addr, err)
+		}
+
+		if string(data) != want {
+			t.Errorf("%v: expected %q, got %q", addr, want, data)
+		}
+	}
+
+	// storages are routed by name only.
+	if _, err := engine.GetSecret(t.Context(), "file:DB_PASSWORD"); err == nil {
+		t.Error("expected error")
+	}
+
+	t.Run("invalid names", func(t *testing.T) {
+		for _, name := range []string{"", "File-Primary", "file primary", "1file"} {
+			_, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+				name: {Scheme: "file", Path: filepath.Join(dir, "primary.env")},
+			})
+			if err == nil {
+				t.Errorf("%q: expected error", name)
+			}
+		}
+	})
+}
+
+func TestDSNFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	primary := write("primary.env", "DB_PASSWORD=primary\n")
+	backup := write("backup.env", "DB_PASSWORD=backup\n")
+	dsnFile := write("dsn.env", "# storages\n"+
+		"file-primary=file://"+backup+"\n"+
+		"file-backup=file://"+backup+"\n"+
+		"file-extra=file://"+primary+"\n")
+
+	engine, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+		// takes precedence over the file.
+		"file-primary": {Scheme: "file", Path: primary},
+		// unset, so taken from the file.
+		"file-backup": nil,
+	}, WithDSNFile(dsnFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = engine.Close() })
+
+	for addr, want := range map[string]string{
+		"file-primary:DB_PASSWORD": "primary",
+		"file-backup:DB_PASSWORD":  "backup",
+		"file-extra:DB_PASSWORD":   "primary",
+	} {
+		secret, err := engine.GetSecret(t.Context(), addr)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", addr, err)
+		}
+
+		data, err := secret.Get(t.Context())
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", addr, err)
+		}
+
+		if string(data) != want {
+			t.Errorf("%v: expected %q, got %q", addr, want, data)
+		}
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := BuildSecretEngine(t.Context(), nil, WithDSNFile(filepath.Join(dir, "missing.env")))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected %v, got %v", os.ErrNotExist, err)
+		}
+	})
+}
+
+func TestJSONFieldAddress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	if err := os.WriteFile(path, []byte(`DB='{"data": {"password": "hunter2"}}'`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := BuildSecretEngine(t.Context(), map[string]*url.URL{
+		"file": {Scheme: "file", Path: path},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := engine.GetSecret(t.Context(), "file:DB#/data/password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data, err := secret.Get(t.Context()); err != nil || string(data) != "hunter2" {
+		t.Fatalf("expected %q, got %q, %v", "hunter2", data, err)
+	}
+
+	secret, err = engine.GetSecret(t.Context(), "file:DB#/data/username")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := secret.Get(t.Context()); !errors.Is(err, secrets.ErrSecretNotFound) {
+		t.Fatalf("expected %v, got %v", secrets.ErrSecretNotFound, err)
+	}
+}
+
+func TestDataStorage(t *testing.T) {
+	for name, tt := range map[string]struct {
+		dsn  string
+		want string
+	}{
+		"plain":          {dsn: "data:,hello", want: "hello"},
+		"base64 binary":  {dsn: "data:application/octet-stream;base64,AAEC/w==", want: "\x00\x01\x02\xff"},
+		"json field":     {dsn: "data:application/json;base64,eyJwYXNzd29yZCI6Imh1bnRlcjIifQ==#password", want: "hunter2"},
+		"json pointer":   {dsn: "data:application/json,%7B%22db%22%3A%7B%22port%22%3A5432%7D%7D#/db/port", want: "5432"},
+		"json untouched": {dsn: "data:application/json,%7B%7D", want: "{}"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dsn, err := url.Parse(tt.dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			engine, err := BuildSecretEngine(t.Context(), map[string]*url.URL{"static": dsn})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			secret, err := engine.GetSecret(t.Context(), "static:any")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if data, err := secret.Get(t.Context()); err != nil || string(data) != tt.want {
+				t.Fatalf("expected %q, got %q, %v", tt.want, data, err)
+			}
+		})
+	}
+
+	t.Run("missing field", func(t *testing.T) {
+		dsn, err := url.Parse("data:application/json;base64,eyJwYXNzd29yZCI6Imh1bnRlcjIifQ==#username")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := BuildSecretEngine(t.Context(), map[string]*url.URL{"static": dsn}); !errors.Is(err, secrets.ErrSecretNotFound) {
+			t.Fatalf("expected %v, got %v", secrets.ErrSecretNotFound, err)
+		}
+	})
+}
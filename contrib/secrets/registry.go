@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"strconv"
+	"strings"
 
 	"github.com/quenbyako/core/secrets"
 	"github.com/vincent-petithory/dataurl"
@@ -13,16 +15,57 @@ import (
 func newSecretStorage(ctx context.Context, u *url.URL) (secrets.Engine, error) {
 	switch u.Scheme {
 	case "file":
-		return NewFile(path.Join(u.Host, u.Path))
+		var opts []FileOption
+		if trim := u.Query().Get("trim"); trim != "" {
+			enabled, err := strconv.ParseBool(trim)
+			if err != nil {
+				return nil, fmt.Errorf("parsing trim parameter: %w", err)
+			}
+			opts = append(opts, WithFileTrim(enabled))
+		}
+		return NewFile(path.Join(u.Host, u.Path), opts...)
 	case "vault":
 		return NewVault(ctx, u)
+	case "consul":
+		return NewConsul(ctx, u)
 	case "data":
-		data, err := dataurl.DecodeString(u.String())
-		if err != nil {
-			return nil, err
-		}
-		return secrets.NewConstantStorage(data.Data), nil
+		return newDataStorage(ctx, u)
 	default:
 		return nil, fmt.Errorf("unsupported secret storage scheme: %q", u.Scheme)
 	}
 }
+
+// newDataStorage decodes data URL u as a constant storage. Fragment of u
+// selects field of JSON payload, see [fieldPointer], e.g.
+// "data:application/json;base64,eyJwYXNzd29yZCI6Imh1bnRlcjIifQ==#password".
+func newDataStorage(ctx context.Context, u *url.URL) (secrets.Engine, error) {
+	raw := *u
+	raw.Fragment, raw.RawFragment = "", ""
+
+	data, err := dataurl.DecodeString(raw.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Fragment == "" {
+		return secrets.NewConstantStorage(data.Data), nil
+	}
+
+	// static value, so field is extracted once, failing early
+	field, err := secrets.NewJSONFieldSecret(secrets.NewPlainSecret(data.Data), fieldPointer(u.Fragment)).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets.NewConstantStorage(field), nil
+}
+
+// fieldPointer converts URL fragment into JSON Pointer: fragments not starting
+// with "/" are shorthand for top-level field, so "password" is "/password".
+func fieldPointer(fragment string) string {
+	if strings.HasPrefix(fragment, "/") {
+		return fragment
+	}
+
+	return "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(fragment)
+}
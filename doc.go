@@ -78,6 +78,10 @@
 //   - Observability(): Grants metrics instrumentation (Metrics interface) when
 //     available; absent contexts remain lightweight.
 //
+// [FeatureAppContext][T]:
+//   - Features(): Exposes an OpenFeature client for feature flag evaluation
+//     when the configuration supplies a provider.
+//
 // This list is not exhaustive, since runtime implementation technically MAY
 // introduce some custom interfaces, however, it's highly recommended to keep the
 // number of such interfaces minimal to reduce complexity.
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/quenbyako/core/internal"
+)
+
+// AddrInUseError is returned by params binding network addresses (listeners,
+// servers) when the address is already taken, usually by another instance of
+// the application.
+type AddrInUseError struct {
+	Network string
+	Addr    string
+	Err     error
+}
+
+func (e *AddrInUseError) Error() string {
+	return fmt.Sprintf("%v address %v is already in use: stop the process listening on it or choose another address", e.Network, e.Addr)
+}
+
+func (e *AddrInUseError) Unwrap() error { return e.Err }
+
+// ListenError wraps error of listening on addr. If the address is already in
+// use, it returns [*AddrInUseError], so it could be detected with
+// [errors.As].
+func ListenError(network, addr string, err error) error {
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return &AddrInUseError{Network: network, Addr: addr, Err: err}
+	}
+
+	return fmt.Errorf("listening on %q %q: %w", network, addr, err)
+}
+
+// ErrAddrNotSet is returned by params binding network addresses (listeners,
+// servers), when they got no address, e.g. the variable is unset and its
+// default is empty. [net/netip.AddrPort] fields fail with it on empty value too.
+var ErrAddrNotSet = internal.ErrAddrNotSet
@@ -0,0 +1,7 @@
+package core
+
+//nolint:gochecknoglobals // exported for tests only.
+var VersionFromBuildInfoData = versionFromBuildInfo
+
+//nolint:gochecknoglobals // exported for tests only.
+var WatchSignals = watchSignals
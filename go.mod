@@ -3,9 +3,20 @@ module github.com/quenbyako/core
 go 1.25.0
 
 require (
+	github.com/open-feature/go-sdk v1.15.1
 	go.opentelemetry.io/otel/metric v1.38.0
+	go.opentelemetry.io/otel/sdk/metric v1.38.0
 	go.opentelemetry.io/otel/trace v1.38.0
 	golang.org/x/mod v0.29.0
 )
 
-require go.opentelemetry.io/otel v1.38.0 // indirect
+require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel v1.38.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.38.0 // indirect
+	go.uber.org/mock v0.5.2 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+)
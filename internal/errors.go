@@ -1,10 +1,15 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
 
+// ErrAddrNotSet is aliased by core.ErrAddrNotSet, so std parsers of addresses
+// could return it.
+var ErrAddrNotSet = errors.New("address is not set")
+
 type UnmarshalFuncError struct {
 	Type reflect.Type
 	Err  error
@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
+	"net/netip"
 	"net/url"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -72,15 +76,27 @@ var (
 		},
 	}
 	envRegistry = map[reflect.Type]parserFunc{
-		reflect.TypeFor[slog.Level]():    parseLogLevel,
-		reflect.TypeFor[url.URL]():       parseURL,
-		reflect.TypeFor[time.Duration](): parseDuration,
-		reflect.TypeFor[time.Location](): parseLocation,
-		reflect.TypeFor[*os.File]():      nil, // TODO: implement that
-		reflect.TypeFor[fs.File]():       nil, // TODO: implement that
+		reflect.TypeFor[slog.Level]():     parseLogLevel,
+		reflect.TypeFor[url.URL]():        parseURL,
+		reflect.TypeFor[netip.Addr]():     parseNetipAddr,
+		reflect.TypeFor[netip.Prefix]():   parseNetipPrefix,
+		reflect.TypeFor[netip.AddrPort](): parseNetipAddrPort,
+		reflect.TypeFor[time.Duration]():  parseDuration,
+		reflect.TypeFor[time.Time]():      parseTime,
+		reflect.TypeFor[time.Location]():  parseLocation,
+		reflect.TypeFor[*os.File]():       parseOSFile,
+		reflect.TypeFor[fs.File]():        parseFSFile,
 	}
 )
 
+// builtinRegistry keeps parsers available before any user registration, see
+// [ResetRegistry].
+var builtinRegistry = maps.Clone(envRegistry) //nolint:gochecknoglobals
+
+// preprocessors normalize raw values before parsing, keyed by target type with
+// pointers stripped.
+var preprocessors = map[reflect.Type]func(string) string{} //nolint:gochecknoglobals
+
 func RegisterEnvParser[T any](parseFunc func(context.Context, string) (T, error)) {
 	typ := reflect.TypeFor[T]()
 	if _, exists := envRegistry[typ]; exists {
@@ -90,12 +106,102 @@ func RegisterEnvParser[T any](parseFunc func(context.Context, string) (T, error)
 	envRegistry[typ] = func(ctx context.Context, v string) (any, error) { return parseFunc(ctx, v) }
 }
 
+// OverrideEnvParser sets parser for T, replacing existing one without panic.
+// Returned function restores the previous parser, or drops the registration,
+// if there was none.
+func OverrideEnvParser[T any](parseFunc func(context.Context, string) (T, error)) (restore func()) {
+	typ := reflect.TypeFor[T]()
+	prev, existed := envRegistry[typ]
+
+	envRegistry[typ] = func(ctx context.Context, v string) (any, error) { return parseFunc(ctx, v) }
+
+	return func() {
+		if existed {
+			envRegistry[typ] = prev
+		} else {
+			delete(envRegistry, typ)
+		}
+	}
+}
+
+func RegisterEnvPreprocessor[T any](f func(raw string) string) {
+	typ := reflect.TypeFor[T]()
+	if _, exists := preprocessors[typ]; exists {
+		panic(fmt.Sprintf("preprocessor for %v already registered", typ))
+	}
+
+	preprocessors[typ] = f
+}
+
+// ResetRegistry drops parsers registered with [RegisterEnvParser] and all
+// preprocessors, keeping built-in parsers. Returned function restores the
+// registry as it was before the reset.
+//
+// It's not synchronized and exists for tests only: use registrytest.Reset,
+// which ties the reset to the test lifetime.
+func ResetRegistry() (restore func()) {
+	saved, savedPreprocessors := envRegistry, preprocessors
+	envRegistry, preprocessors = maps.Clone(builtinRegistry), map[reflect.Type]func(string) string{}
+
+	return func() { envRegistry, preprocessors = saved, savedPreprocessors }
+}
+
 type parserFunc = func(context.Context, string) (any, error)
 
+// RegisteredTypes returns types having parser in the registry, sorted by
+// their names. Placeholders without parser are skipped.
+func RegisteredTypes() []reflect.Type {
+	res := make([]reflect.Type, 0, len(envRegistry))
+	for typ, f := range envRegistry {
+		if f != nil {
+			res = append(res, typ)
+		}
+	}
+
+	slices.SortFunc(res, func(a, b reflect.Type) int { return strings.Compare(a.String(), b.String()) })
+
+	return res
+}
+
 // Deprecated: This is a temporary function to aid migration. Use [GetParseFunc] instead.
-func GetAllParseFunc() map[reflect.Type]parserFunc { return envRegistry }
+func GetAllParseFunc() map[reflect.Type]parserFunc {
+	res := make(map[reflect.Type]parserFunc, len(envRegistry))
+	for typ, f := range envRegistry {
+		if f != nil {
+			f = preprocessed(typ, f)
+		}
+
+		res[typ] = f
+	}
+
+	return res
+}
 
 func GetParseFunc(typ reflect.Type) (f parserFunc, ptrDepth int, ok bool) {
+	f, ptrDepth, ok = getParseFunc(typ)
+	if !ok {
+		return nil, 0, false
+	}
+
+	return preprocessed(typ, f), ptrDepth, true
+}
+
+// preprocessed applies preprocessor registered for typ (or type it points to)
+// to raw value before parsing it with f.
+func preprocessed(typ reflect.Type, f parserFunc) parserFunc {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	preprocess, ok := preprocessors[typ]
+	if !ok {
+		return f
+	}
+
+	return func(ctx context.Context, raw string) (any, error) { return f(ctx, preprocess(raw)) }
+}
+
+func getParseFunc(typ reflect.Type) (f parserFunc, ptrDepth int, ok bool) {
 	// unpacking pointers
 	inner := typ
 	depth := 0
@@ -0,0 +1,21 @@
+// Package registrytest provides helpers isolating tests from the global env
+// parser registry.
+package registrytest
+
+import (
+	"testing"
+
+	"github.com/quenbyako/core/internal"
+)
+
+// Reset drops parsers registered with core.RegisterEnvParser and
+// preprocessors registered with core.RegisterEnvPreprocessor for the duration
+// of the test, so the test can register its own ones without panics. Original
+// registrations are restored at test cleanup.
+//
+// Tests calling Reset must not run in parallel with tests parsing values.
+func Reset(tb testing.TB) {
+	tb.Helper()
+
+	tb.Cleanup(internal.ResetRegistry())
+}
@@ -0,0 +1,86 @@
+package registrytest_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quenbyako/core"
+	. "github.com/quenbyako/core/internal/registrytest"
+)
+
+type custom struct{ value string }
+
+func parseCustom(prefix string) func(context.Context, string) (custom, error) {
+	return func(_ context.Context, v string) (custom, error) { return custom{prefix + v}, nil }
+}
+
+func parse(t *testing.T, v string) (any, bool) {
+	t.Helper()
+
+	f, _, ok := core.GetParseFunc(reflect.TypeFor[custom]())
+	if !ok {
+		return nil, false
+	}
+
+	res, err := f(t.Context(), v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return res, true
+}
+
+func TestReset(t *testing.T) {
+	core.RegisterEnvParser(parseCustom("original:"))
+
+	t.Run("reset", func(t *testing.T) {
+		Reset(t)
+
+		if _, ok := parse(t, "v"); ok {
+			t.Fatal("user parser must be removed")
+		}
+
+		if _, _, ok := core.GetParseFunc(reflect.TypeFor[time.Duration]()); !ok {
+			t.Error("built-in parser must be kept")
+		}
+
+		// must not panic
+		core.RegisterEnvParser(parseCustom("replaced:"))
+
+		if res, _ := parse(t, "v"); res != (custom{"replaced:v"}) {
+			t.Errorf("expected re-registered parser, got %v", res)
+		}
+	})
+
+	if res, _ := parse(t, "v"); res != (custom{"original:v"}) {
+		t.Errorf("expected original parser restored, got %v", res)
+	}
+}
+
+func TestResetPreprocessors(t *testing.T) {
+	Reset(t)
+	core.RegisterEnvParser(parseCustom(""))
+	core.RegisterEnvPreprocessor[custom](func(raw string) string { return "original:" + raw })
+
+	t.Run("reset", func(t *testing.T) {
+		Reset(t)
+		core.RegisterEnvParser(parseCustom(""))
+
+		if res, _ := parse(t, "v"); res != (custom{"v"}) {
+			t.Fatalf("preprocessor must be removed, got %v", res)
+		}
+
+		// must not panic
+		core.RegisterEnvPreprocessor[custom](func(raw string) string { return "replaced:" + raw })
+
+		if res, _ := parse(t, "v"); res != (custom{"replaced:v"}) {
+			t.Errorf("expected re-registered preprocessor, got %v", res)
+		}
+	})
+
+	if res, _ := parse(t, "v"); res != (custom{"original:v"}) {
+		t.Errorf("expected original preprocessor restored, got %v", res)
+	}
+}
@@ -3,8 +3,11 @@ package internal
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"net/netip"
 	"net/url"
+	"os"
 	"strings"
 	"time"
 )
@@ -19,6 +22,70 @@ func parseURL(_ context.Context, v string) (any, error) {
 	return *u, nil
 }
 
+//nolint:ireturn // well, that's how env works
+func parseNetipAddr(_ context.Context, v string) (any, error) {
+	addr, err := netip.ParseAddr(v)
+	if err != nil {
+		return nil, fmt.Errorf("parse ip address: %w", err)
+	}
+
+	return addr, nil
+}
+
+//nolint:ireturn // well, that's how env works
+func parseNetipPrefix(_ context.Context, v string) (any, error) {
+	prefix, err := netip.ParsePrefix(v)
+	if err != nil {
+		return nil, fmt.Errorf("parse ip prefix: %w", err)
+	}
+
+	return prefix, nil
+}
+
+//nolint:ireturn // well, that's how env works
+func parseNetipAddrPort(_ context.Context, v string) (any, error) {
+	if v == "" {
+		return nil, fmt.Errorf("parse ip address with port: %w", ErrAddrNotSet)
+	}
+
+	addrPort, err := netip.ParseAddrPort(v)
+	if err != nil {
+		return nil, fmt.Errorf("parse ip address with port: %w", err)
+	}
+
+	return addrPort, nil
+}
+
+type ctxLayoutKey struct{}
+
+// WithLayout attaches a per-field layout hint (e.g. a [time.Time] format) to
+// the parser context.
+func WithLayout(ctx context.Context, layout string) context.Context {
+	return context.WithValue(ctx, ctxLayoutKey{}, layout)
+}
+
+// LayoutFromContext returns the layout hint attached with [WithLayout], if any.
+func LayoutFromContext(ctx context.Context) (string, bool) {
+	layout, ok := ctx.Value(ctxLayoutKey{}).(string)
+
+	return layout, ok && layout != ""
+}
+
+//nolint:ireturn // well, that's how env works
+func parseTime(ctx context.Context, v string) (any, error) {
+	layout, ok := LayoutFromContext(ctx)
+	if !ok {
+		layout = time.RFC3339
+	}
+
+	t, err := time.Parse(layout, v)
+	if err != nil {
+		return nil, fmt.Errorf("parse time: %w", err)
+	}
+
+	return t, nil
+}
+
 //nolint:ireturn // well, that's how env works
 func parseDuration(_ context.Context, v string) (any, error) {
 	d, err := time.ParseDuration(v)
@@ -71,3 +138,84 @@ func parseLogLevel(_ context.Context, input string) (any, error) {
 		return l, nil
 	}
 }
+
+// File open modes, selected by layout hint of [*os.File] fields.
+const (
+	FileModeRead   = "read"
+	FileModeCreate = "create"
+	FileModeAppend = "append"
+)
+
+type ctxFSKey struct{}
+
+// WithFS attaches filesystem [fs.File] values are opened against.
+func WithFS(ctx context.Context, fsys fs.FS) context.Context {
+	return context.WithValue(ctx, ctxFSKey{}, fsys)
+}
+
+// FSFromContext returns filesystem attached with [WithFS], if any.
+//
+//nolint:ireturn // returns interface on intention.
+func FSFromContext(ctx context.Context) (fs.FS, bool) {
+	fsys, ok := ctx.Value(ctxFSKey{}).(fs.FS)
+
+	return fsys, ok && fsys != nil
+}
+
+// parseOSFile opens file at path v, for reading by default. Layout hint
+// selects another mode: [FileModeCreate] truncates or creates the file for
+// writing, [FileModeAppend] appends to it. Empty path leaves the file nil.
+//
+//nolint:ireturn // well, that's how env works
+func parseOSFile(ctx context.Context, v string) (any, error) {
+	if v == "" {
+		return (*os.File)(nil), nil
+	}
+
+	mode, _ := LayoutFromContext(ctx)
+
+	var (
+		f   *os.File
+		err error
+	)
+
+	switch mode {
+	case "", FileModeRead:
+		f, err = os.Open(v)
+	case FileModeCreate:
+		f, err = os.Create(v)
+	case FileModeAppend:
+		f, err = os.OpenFile(v, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600) //nolint:mnd // default permissions
+	default:
+		return nil, fmt.Errorf("unknown file mode %q", mode)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+
+	return f, nil
+}
+
+// parseFSFile opens file at path v for reading against filesystem attached
+// with [WithFS], or against OS filesystem, if there is none. Empty path leaves
+// the file nil.
+//
+//nolint:ireturn // well, that's how env works
+func parseFSFile(ctx context.Context, v string) (any, error) {
+	if v == "" {
+		return nil, nil //nolint:nilnil // nil file is valid value
+	}
+
+	fsys, ok := FSFromContext(ctx)
+	if !ok {
+		return parseOSFile(ctx, v)
+	}
+
+	f, err := fsys.Open(v)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+type teeHandler struct {
+	handlers []slog.Handler
+}
+
+var _ slog.Handler = (*teeHandler)(nil) //nolint:grouper // type check
+
+// NewTeeHandler returns a [slog.Handler] duplicating records to every of
+// handlers, e.g. human-readable stderr and machine-readable sink. Each handler
+// receives only records of levels it is enabled for, errors of handlers are
+// joined.
+//
+//nolint:ireturn // returns interface on intention.
+func NewTeeHandler(handlers ...slog.Handler) slog.Handler {
+	return &teeHandler{handlers: handlers}
+}
+
+func (h *teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+
+		// handlers may modify record, so each one gets its own copy.
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+//nolint:ireturn // returns interface on intention.
+func (h *teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+//nolint:ireturn // returns interface on intention.
+func (h *teeHandler) WithGroup(name string) slog.Handler {
+	return h.derive(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *teeHandler) derive(f func(slog.Handler) slog.Handler) *teeHandler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = f(handler)
+	}
+
+	return &teeHandler{handlers: handlers}
+}
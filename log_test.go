@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	. "github.com/quenbyako/core"
+)
+
+// failingHandler accepts every record, failing to handle it.
+type failingHandler struct {
+	slog.Handler
+
+	err error
+}
+
+func (h failingHandler) Handle(context.Context, slog.Record) error { return h.err }
+
+// decodeRecords decodes JSON log records of buf, dropping their time.
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for line := range bytes.Lines(buf.Bytes()) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatal(err)
+		}
+
+		delete(record, "time")
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestTeeHandler(t *testing.T) {
+	var first, second bytes.Buffer
+
+	logger := slog.New(NewTeeHandler(
+		slog.NewJSONHandler(&first, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewJSONHandler(&second, &slog.HandlerOptions{Level: slog.LevelWarn}),
+	))
+
+	logger.With("app", "demo").WithGroup("request").Warn("slow", "id", 42)
+	logger.Debug("verbose")
+
+	firstLines, secondLines := decodeRecords(t, &first), decodeRecords(t, &second)
+	if len(firstLines) != 2 || len(secondLines) != 1 {
+		t.Fatalf("expected 2 and 1 records by levels, got %v and %v", firstLines, secondLines)
+	}
+
+	want := `{"app":"demo","level":"WARN","msg":"slow","request":{"id":42}}`
+	for _, record := range []map[string]any{firstLines[0], secondLines[0]} {
+		if got, _ := json.Marshal(record); string(got) != want {
+			t.Errorf("expected %v, got %s", want, got)
+		}
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		handler := NewTeeHandler(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError}))
+
+		if handler.Enabled(t.Context(), slog.LevelInfo) {
+			t.Error("expected info level to be disabled")
+		}
+
+		if !handler.Enabled(t.Context(), slog.LevelError) {
+			t.Error("expected error level to be enabled")
+		}
+
+		if NewTeeHandler().Enabled(t.Context(), slog.LevelError) {
+			t.Error("expected handler without children to be disabled")
+		}
+	})
+
+	t.Run("errors are joined", func(t *testing.T) {
+		errFirst, errSecond := errors.New("first"), errors.New("second")
+
+		handler := NewTeeHandler(
+			failingHandler{Handler: slog.NewTextHandler(io.Discard, nil), err: errFirst},
+			failingHandler{Handler: slog.NewTextHandler(io.Discard, nil), err: errSecond},
+		)
+
+		err := handler.Handle(t.Context(), slog.NewRecord(time.Now(), slog.LevelError, "msg", 0))
+		if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+			t.Fatalf("expected both errors, got %v", err)
+		}
+	})
+}
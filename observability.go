@@ -43,3 +43,53 @@ func (n *noopMetrics) Enabled(context.Context, slog.Level) bool  { return false
 func (n *noopMetrics) Handle(context.Context, slog.Record) error { return nil }
 func (n *noopMetrics) WithAttrs(attrs []slog.Attr) slog.Handler  { return n }
 func (n *noopMetrics) WithGroup(name string) slog.Handler        { return n }
+
+// MetricsKit creates instruments of the action from a meter named after the
+// application. When observability is absent, instruments are no-ops, so
+// actions can record unconditionally.
+type MetricsKit struct {
+	meter metric.Meter
+}
+
+// NewMetricsKit returns a kit using meter provider of the
+// [ObservabilityAppContext] capability of ctx, if any.
+func NewMetricsKit[T ActionConfig](ctx AppContext[T]) MetricsKit {
+	var provider metric.MeterProvider = noopMetric.NewMeterProvider()
+	if m, ok := Observability(ctx); ok && m != nil {
+		provider = m
+	}
+
+	name, _ := ctx.Name().Name()
+
+	return MetricsKit{meter: provider.Meter(name)}
+}
+
+// Meter returns the underlying meter, for instruments not covered by the kit.
+//
+//nolint:ireturn // returns interface on intention.
+func (k MetricsKit) Meter() metric.Meter { return k.meter }
+
+// Counter creates a monotonic counter, e.g. of processed requests. Error is
+// returned for invalid names or options, usable instrument is returned anyway.
+//
+//nolint:ireturn,wrapcheck // returns interface on intention.
+func (k MetricsKit) Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return k.meter.Int64Counter(name, opts...)
+}
+
+// Histogram creates a histogram of measured values, e.g. request durations
+// with metric.WithUnit("s"). Error semantics are the same as for
+// [MetricsKit.Counter].
+//
+//nolint:ireturn,wrapcheck // returns interface on intention.
+func (k MetricsKit) Histogram(name string, opts ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return k.meter.Float64Histogram(name, opts...)
+}
+
+// Gauge creates a gauge of current values, e.g. queue length. Error semantics
+// are the same as for [MetricsKit.Counter].
+//
+//nolint:ireturn,wrapcheck // returns interface on intention.
+func (k MetricsKit) Gauge(name string, opts ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	return k.meter.Float64Gauge(name, opts...)
+}
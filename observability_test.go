@@ -0,0 +1,101 @@
+package core_test
+
+import (
+	"testing"
+
+	. "github.com/quenbyako/core"
+	"go.opentelemetry.io/otel/metric"
+	noopMetric "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type bareAppContext struct{}
+
+func (bareAppContext) Name() AppName                     { return AppName{} }
+func (bareAppContext) Version() AppVersion               { return AppVersion{} }
+func (bareAppContext) Config() UnimplementedActionConfig { return UnimplementedActionConfig{} }
+
+type observableAppContext struct {
+	bareAppContext
+
+	metrics Metrics
+}
+
+func (c observableAppContext) Observability() Metrics { return c.metrics }
+
+type meterMetrics struct {
+	Metrics
+
+	provider metric.MeterProvider
+}
+
+//nolint:ireturn // implements interface.
+func (m meterMetrics) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return m.provider.Meter(name, opts...)
+}
+
+func TestMetricsKit(t *testing.T) {
+	t.Run("with observability", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+		t.Cleanup(func() { _ = provider.Shutdown(t.Context()) })
+
+		kit := NewMetricsKit[UnimplementedActionConfig](observableAppContext{
+			metrics: meterMetrics{Metrics: NoopMetrics(), provider: provider},
+		})
+
+		counter, err := kit.Counter("jobs.processed")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		histogram, err := kit.Histogram("jobs.duration", metric.WithUnit("s"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gauge, err := kit.Gauge("jobs.queued")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counter.Add(t.Context(), 2)
+		histogram.Record(t.Context(), 0.5)
+		gauge.Record(t.Context(), 7)
+
+		var data metricdata.ResourceMetrics
+		if err := reader.Collect(t.Context(), &data); err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]bool)
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				got[m.Name] = true
+			}
+		}
+
+		for _, name := range []string{"jobs.processed", "jobs.duration", "jobs.queued"} {
+			if !got[name] {
+				t.Errorf("metric %q was not recorded", name)
+			}
+		}
+	})
+
+	t.Run("without observability", func(t *testing.T) {
+		kit := NewMetricsKit[UnimplementedActionConfig](bareAppContext{})
+
+		counter, err := kit.Counter("jobs.processed")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// must not panic
+		counter.Add(t.Context(), 1)
+
+		if _, ok := counter.(noopMetric.Int64Counter); !ok {
+			t.Errorf("expected no-op counter, got %T", counter)
+		}
+	})
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"reflect"
+	"strings"
 
+	"github.com/open-feature/go-sdk/openfeature"
 	"github.com/quenbyako/core/internal"
 	"github.com/quenbyako/core/secrets"
 	"go.opentelemetry.io/otel/metric"
@@ -23,7 +28,8 @@ import (
 //     registered separately if they require distinct parsing.
 //
 // Panics:
-//   - If a parser for T is already present.
+//   - If a parser for T is already present, including built-in ones. Use
+//     [OverrideEnvParser] to replace it.
 //
 // Concurrency:
 //   - Expected to run during startup before other goroutines; no synchronization.
@@ -51,6 +57,96 @@ func RegisterEnvParser[T any](f func(context.Context, string) (T, error)) {
 	internal.RegisterEnvParser(f)
 }
 
+// OverrideEnvParser replaces parser for T (e.g. built-in [time.Duration] or
+// [url.URL] one) without panicking, registering it if T has no parser yet.
+// Returned function restores the previous parser, so tests can revert
+// global state:
+//
+//	t.Cleanup(core.OverrideEnvParser(parseSeconds))
+//
+// Like [RegisterEnvParser], it isn't synchronized: call it during startup or
+// from tests not running in parallel with parsing.
+func OverrideEnvParser[T any](f func(context.Context, string) (T, error)) (restore func()) {
+	return internal.OverrideEnvParser(f)
+}
+
+// RegisterEnumParser registers a parser for enum type T (e.g. generated by
+// protoc or stringer), mapping names to values case-insensitively. name2value
+// is usually the generated "_value" map of a protobuf enum:
+//
+//	func init() {
+//	    core.RegisterEnumParser(pb.State_value)
+//	}
+//
+// The same registration rules as for [RegisterEnvParser] apply.
+func RegisterEnumParser[T ~int32](name2value map[string]T) {
+	values := make(map[string]T, len(name2value))
+	for name, value := range name2value {
+		values[strings.ToLower(name)] = value
+	}
+
+	RegisterEnvParser(func(_ context.Context, raw string) (T, error) {
+		if value, ok := values[strings.ToLower(raw)]; ok {
+			return value, nil
+		}
+
+		return 0, fmt.Errorf("unknown %v value %q", reflect.TypeFor[T](), raw)
+	})
+}
+
+// RegisterEnvPreprocessor registers a function normalizing raw values of type
+// T (or pointers to T) before they are parsed, e.g. to uppercase a country
+// code or strip a prefix from a token. It applies to both built-in parsers and
+// ones registered via [RegisterEnvParser], including slice and map elements.
+//
+// Like [RegisterEnvParser], it is intended to be called from init functions
+// and panics if a preprocessor for T is already registered.
+func RegisterEnvPreprocessor[T any](f func(raw string) string) {
+	internal.RegisterEnvPreprocessor[T](f)
+}
+
+// WithParseLayout returns a derived context carrying a per-field layout hint
+// for parsers registered via [RegisterEnvParser]. The env parser attaches the
+// value of the `envLayout` struct tag this way; the built-in [time.Time]
+// parser uses it as the [time.Parse] layout (defaulting to [time.RFC3339]).
+func WithParseLayout(ctx context.Context, layout string) context.Context {
+	return internal.WithLayout(ctx, layout)
+}
+
+// File open modes of [*os.File] fields, set as `envLayout` struct tag. Files
+// are opened for reading by default.
+const (
+	FileModeRead   = internal.FileModeRead
+	FileModeCreate = internal.FileModeCreate
+	FileModeAppend = internal.FileModeAppend
+)
+
+// WithParseFS returns a derived context carrying filesystem, which the
+// built-in [fs.File] parser opens paths against, e.g. [embed.FS] or
+// [fstest.MapFS] in tests. Without it, paths are opened on OS filesystem.
+//
+// Built-in [*os.File] parser always uses OS filesystem, opening the file in
+// mode set by `envLayout` tag (see [FileModeRead]). For both of them empty
+// path leaves the field nil. Opened files are owned by the caller, e.g. the
+// runtime closes them on shutdown, or once parsing or setup fails.
+func WithParseFS(ctx context.Context, fsys fs.FS) context.Context {
+	return internal.WithFS(ctx, fsys)
+}
+
+// ParseLayoutFromContext extracts a layout hint attached with
+// [WithParseLayout]. The boolean is false when no (or an empty) layout is set.
+func ParseLayoutFromContext(ctx context.Context) (string, bool) {
+	return internal.LayoutFromContext(ctx)
+}
+
+// RegisteredEnvParsers returns a snapshot of types with parsers registered
+// via [RegisterEnvParser] (including ones registered by imported packages) or
+// built in, sorted by type name. Types parsed by kind (e.g. plain strings and
+// numbers) or via [encoding.TextUnmarshaler] are not listed.
+func RegisteredEnvParsers() []reflect.Type {
+	return internal.RegisteredTypes()
+}
+
 func GetParseFunc(typ reflect.Type) (f func(context.Context, string) (any, error), ptrDepth int, ok bool) {
 	return internal.GetParseFunc(typ)
 }
@@ -71,17 +167,46 @@ type EnvParam interface {
 	Shutdown(ctx context.Context, data *ShutdownData) error
 }
 
+// Servable is an optional extension of [EnvParam] for params that run a
+// serving loop (e.g. network servers). The runtime calls Serve of every
+// servable param concurrently with the action, once the action reports its
+// setup is done with [Ready], or returns with zero exit code. Serve must block
+// until ctx is cancelled, then stop gracefully and return.
+//
+// If the action returns zero exit code, servers keep serving until the root
+// context is cancelled (e.g. by a signal), so actions may only register their
+// handlers and return. Non-zero exit code cancels the serve context at once.
+// Either way servers shut down gracefully before [EnvParam.Shutdown] is
+// called. Actions never call Serve themselves.
+type Servable interface {
+	Serve(ctx context.Context) error
+}
+
+// Drainable is an optional extension of [EnvParam] for params that can stop
+// accepting new work (e.g. pause accepting connections or disable keep-alives)
+// while in-flight work finishes. The runtime calls Drain once, when a drain
+// signal is received (see runtime.WithDrainSignal). Drain does not mean
+// shutdown: the context stays alive and the action keeps running.
+type Drainable interface {
+	Drain(ctx context.Context) error
+}
+
 // ConfigureData provides foundational wiring inputs for [EnvParam.Configure].
-// Fields may be nil when a capability is absent (e.g., Secrets, Metric).
+// Fields may be nil when a capability is absent (e.g., Secrets, Metric,
+// Features). Stdin and Stdout are the pipeline streams of the action (see
+// [Pipeline]); params must not close them.
 // Implementations should not mutate shared values.
 type ConfigureData struct {
-	AppCert tls.Certificate
-	Logger  slog.Handler
-	Secrets secrets.Engine
-	Metric  metric.MeterProvider
-	Trace   trace.TracerProvider
-	Pool    *x509.CertPool
-	Version AppVersion
+	AppCert  tls.Certificate
+	Logger   slog.Handler
+	Secrets  secrets.Engine
+	Metric   metric.MeterProvider
+	Trace    trace.TracerProvider
+	Features openfeature.IClient
+	Pool     *x509.CertPool
+	Stdin    io.Reader
+	Stdout   io.Writer
+	Version  AppVersion
 }
 
 // AcquireData inherits configuration values and allows acquisition logic
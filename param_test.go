@@ -0,0 +1,224 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/quenbyako/core"
+	"github.com/quenbyako/core/internal/registrytest"
+)
+
+type registeredValue string
+
+func TestRegisteredEnvParsers(t *testing.T) {
+	registrytest.Reset(t)
+	RegisterEnvParser(func(_ context.Context, v string) (registeredValue, error) { return registeredValue(v), nil })
+
+	types := RegisteredEnvParsers()
+
+	for _, want := range []reflect.Type{
+		reflect.TypeFor[url.URL](),
+		reflect.TypeFor[time.Duration](),
+		reflect.TypeFor[registeredValue](),
+	} {
+		if !slices.Contains(types, want) {
+			t.Errorf("expected %v in %v", want, types)
+		}
+	}
+
+	if !slices.IsSortedFunc(types, func(a, b reflect.Type) int { return strings.Compare(a.String(), b.String()) }) {
+		t.Errorf("expected types sorted by name, got %v", types)
+	}
+
+	// snapshot is not shared with registry.
+	types[0] = nil
+	if RegisteredEnvParsers()[0] == nil {
+		t.Error("expected registry not to be modified")
+	}
+}
+
+func parseSeconds(_ context.Context, v string) (time.Duration, error) {
+	seconds, err := strconv.Atoi(v)
+
+	return time.Duration(seconds) * time.Second, err
+}
+
+func TestOverrideEnvParser(t *testing.T) {
+	parse := func(t *testing.T, raw string) (any, error) {
+		t.Helper()
+
+		f, _, ok := GetParseFunc(reflect.TypeFor[time.Duration]())
+		if !ok {
+			t.Fatal("expected duration parser")
+		}
+
+		return f(t.Context(), raw)
+	}
+
+	restore := OverrideEnvParser(parseSeconds)
+
+	if v, err := parse(t, "90"); err != nil || v != 90*time.Second {
+		t.Errorf("expected overridden parser, got %v, %v", v, err)
+	}
+
+	restore()
+
+	if v, err := parse(t, "1m30s"); err != nil || v != 90*time.Second {
+		t.Errorf("expected restored parser, got %v, %v", v, err)
+	}
+
+	if _, err := parse(t, "90"); err == nil {
+		t.Error("expected restored parser to reject integer seconds")
+	}
+
+	t.Run("new type", func(t *testing.T) {
+		type seconds time.Duration
+
+		restore := OverrideEnvParser(func(ctx context.Context, v string) (seconds, error) {
+			d, err := parseSeconds(ctx, v)
+
+			return seconds(d), err
+		})
+
+		if !slices.Contains(RegisteredEnvParsers(), reflect.TypeFor[seconds]()) {
+			t.Error("expected parser to be registered")
+		}
+
+		restore()
+
+		if slices.Contains(RegisteredEnvParsers(), reflect.TypeFor[seconds]()) {
+			t.Error("expected registration to be dropped")
+		}
+	})
+}
+
+func TestAddrPortParser(t *testing.T) {
+	f, _, ok := GetParseFunc(reflect.TypeFor[netip.AddrPort]())
+	if !ok {
+		t.Fatal("expected netip.AddrPort parser")
+	}
+
+	v, err := f(t.Context(), "127.0.0.1:8080")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := netip.MustParseAddrPort("127.0.0.1:8080"); v != want {
+		t.Errorf("expected %v, got %v", want, v)
+	}
+
+	if _, err := f(t.Context(), ""); !errors.Is(err, ErrAddrNotSet) {
+		t.Errorf("expected %v, got %v", ErrAddrNotSet, err)
+	}
+
+	if _, err := f(t.Context(), "127.0.0.1"); err == nil || errors.Is(err, ErrAddrNotSet) {
+		t.Errorf("expected parsing error, got %v", err)
+	}
+}
+
+func TestFileParsers(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "data.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	parse := func(t *testing.T, ctx context.Context, typ reflect.Type, raw string) any {
+		t.Helper()
+
+		f, _, ok := GetParseFunc(typ)
+		if !ok {
+			t.Fatalf("expected %v parser", typ)
+		}
+
+		v, err := f(ctx, raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if c, ok := v.(io.Closer); ok && v != (*os.File)(nil) {
+			t.Cleanup(func() { _ = c.Close() })
+		}
+
+		return v
+	}
+
+	readAll := func(t *testing.T, r any) string {
+		t.Helper()
+
+		data, err := io.ReadAll(r.(io.Reader))
+		if err != nil {
+			t.Fatalf("reading file: %v", err)
+		}
+
+		return string(data)
+	}
+
+	t.Run("os file", func(t *testing.T) {
+		if got := readAll(t, parse(t, t.Context(), reflect.TypeFor[*os.File](), path)); got != "hello" {
+			t.Errorf("expected %q, got %q", "hello", got)
+		}
+
+		for mode, want := range map[string]string{FileModeCreate: "written", FileModeAppend: "hellowritten"} {
+			path := filepath.Join(dir, mode+".txt")
+			if err := os.WriteFile(path, []byte("hello"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			f := parse(t, WithParseLayout(t.Context(), mode), reflect.TypeFor[*os.File](), path).(*os.File)
+			if _, err := f.WriteString("written"); err != nil {
+				t.Fatalf("%v: writing file: %v", mode, err)
+			}
+
+			if data, _ := os.ReadFile(path); string(data) != want {
+				t.Errorf("%v: expected %q, got %q", mode, want, data)
+			}
+		}
+
+		if f := parse(t, t.Context(), reflect.TypeFor[*os.File](), ""); f != (*os.File)(nil) {
+			t.Errorf("expected nil file, got %v", f)
+		}
+	})
+
+	t.Run("os file errors", func(t *testing.T) {
+		f, _, _ := GetParseFunc(reflect.TypeFor[*os.File]())
+
+		if _, err := f(t.Context(), filepath.Join(dir, "missing.txt")); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected %v, got %v", fs.ErrNotExist, err)
+		}
+
+		if _, err := f(WithParseLayout(t.Context(), "truncate"), path); err == nil {
+			t.Error("expected unknown mode error")
+		}
+	})
+
+	t.Run("fs file", func(t *testing.T) {
+		fsys := fstest.MapFS{"config/app.yaml": {Data: []byte("embedded")}}
+
+		if got := readAll(t, parse(t, WithParseFS(t.Context(), fsys), reflect.TypeFor[fs.File](), "config/app.yaml")); got != "embedded" {
+			t.Errorf("expected %q, got %q", "embedded", got)
+		}
+
+		if got := readAll(t, parse(t, t.Context(), reflect.TypeFor[fs.File](), path)); got != "hello" {
+			t.Errorf("expected %q from OS filesystem, got %q", "hello", got)
+		}
+
+		if f := parse(t, t.Context(), reflect.TypeFor[fs.File](), ""); f != nil {
+			t.Errorf("expected nil file, got %v", f)
+		}
+	})
+}
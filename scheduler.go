@@ -3,7 +3,10 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime/debug"
 	"sync"
+	"time"
 )
 
 // RunJobs concurrently executes the provided job functions, cancelling all
@@ -21,6 +24,8 @@ import (
 //     suppressed.
 //   - All other errors are collected and returned via [errors.Join].
 //   - If no non-context errors occur, the function returns nil.
+//   - A panicking job is recovered and reported as [*PanicError], triggering
+//     cancellation like any other failure.
 //
 // Cancellation Semantics:
 //   - Caller cancellation (ctx) propagates to all jobs.
@@ -41,6 +46,20 @@ import (
 //	    // handle joined errors
 //	}
 func RunJobs(ctx context.Context, jobs ...func(context.Context) error) error {
+	return RunJobsLimit(ctx, len(jobs), jobs...)
+}
+
+// RunJobsLimit behaves like [RunJobs], but runs at most n jobs at the same
+// time. Cancellation and error aggregation semantics are identical.
+//
+// Jobs waiting for a free slot are skipped once the derived context is
+// cancelled (either by the caller or by the first failure). A non-positive n
+// means no limit.
+func RunJobsLimit(ctx context.Context, n int, jobs ...func(context.Context) error) error {
+	if n <= 0 || n > len(jobs) {
+		n = len(jobs)
+	}
+
 	jobCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -48,13 +67,21 @@ func RunJobs(ctx context.Context, jobs ...func(context.Context) error) error {
 		errs    []error
 		errsMux sync.Mutex
 		wg      sync.WaitGroup
+		sem     = make(chan struct{}, n)
 	)
 
 	wg.Add(len(jobs))
 
 	for i, job := range jobs {
 		go func(_ int, job func(context.Context) error) {
-			if err := omitContextErr(job(jobCtx)); err != nil {
+			defer wg.Done()
+
+			if !acquire(jobCtx, sem) {
+				return
+			}
+			defer func() { <-sem }()
+
+			if err := omitContextErr(runJob(jobCtx, job)); err != nil {
 				errsMux.Lock()
 
 				errs = append(errs, err)
@@ -63,8 +90,6 @@ func RunJobs(ctx context.Context, jobs ...func(context.Context) error) error {
 
 				cancel()
 			}
-
-			wg.Done()
 		}(i, job)
 	}
 
@@ -73,6 +98,151 @@ func RunJobs(ctx context.Context, jobs ...func(context.Context) error) error {
 	return errors.Join(errs...)
 }
 
+// ErrJobTimeout is returned by [RunJobsTimeout] for jobs exceeding their
+// timeout. Unlike [context.DeadlineExceeded], it's never suppressed.
+var ErrJobTimeout = errors.New("job timed out")
+
+// RunJobsTimeout behaves like [RunJobs], but bounds each job by perJob
+// timeout, so single stuck job can't stall the whole group. Job failing after
+// its own timeout expired is reported as [ErrJobTimeout] and cancels the rest,
+// like any other failure, while cancellation by the caller is still
+// suppressed. A non-positive perJob means no timeout.
+func RunJobsTimeout(ctx context.Context, perJob time.Duration, jobs ...func(context.Context) error) error {
+	if perJob <= 0 {
+		return RunJobs(ctx, jobs...)
+	}
+
+	bounded := make([]func(context.Context) error, len(jobs))
+	for i, job := range jobs {
+		bounded[i] = func(ctx context.Context) error {
+			jobCtx, cancel := context.WithTimeoutCause(ctx, perJob, ErrJobTimeout)
+			defer cancel()
+
+			err := job(jobCtx)
+			if err != nil && ctx.Err() == nil && errors.Is(context.Cause(jobCtx), ErrJobTimeout) {
+				return fmt.Errorf("job #%v exceeded %v: %w", i, perJob, ErrJobTimeout)
+			}
+
+			return err
+		}
+	}
+
+	return RunJobs(ctx, bounded...)
+}
+
+// RaceJobs concurrently executes the provided job functions and returns the
+// first successful result. Remaining jobs are cancelled as soon as one of them
+// succeeds.
+//
+// Errors equal to [context.Canceled] or [context.DeadlineExceeded] are
+// suppressed, all other failures are returned via [errors.Join] only if every
+// job failed. If no job succeeded and every failure was suppressed, the
+// caller's context error is returned instead.
+//
+// Panicking jobs are recovered and reported as [*PanicError].
+func RaceJobs[T any](ctx context.Context, jobs ...func(context.Context) (T, error)) (T, error) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	results := make(chan result, len(jobs))
+
+	for _, job := range jobs {
+		go func(job func(context.Context) (T, error)) {
+			var res result
+
+			res.err = runJob(jobCtx, func(ctx context.Context) (err error) {
+				res.value, err = job(ctx)
+
+				return err
+			})
+
+			results <- res
+		}(job)
+	}
+
+	var errs []error
+
+	for range jobs {
+		res := <-results
+		if res.err == nil {
+			return res.value, nil
+		}
+
+		if err := omitContextErr(res.err); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var zero T
+
+	if len(errs) == 0 {
+		return zero, ctx.Err() //nolint:wrapcheck // caller context error
+	}
+
+	return zero, errors.Join(errs...)
+}
+
+// acquire takes a free slot from sem, preferring a free slot over
+// cancellation, so unbounded runs never skip jobs.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	default:
+	}
+
+	select {
+	case sem <- struct{}{}:
+		// both cases could be ready at once, cancellation wins for jobs which
+		// were waiting.
+		if ctx.Err() != nil {
+			<-sem
+
+			return false
+		}
+
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// PanicError is returned by [RunJobs] when a job panics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+func runJob(ctx context.Context, job func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return job(ctx)
+}
+
 func omitContextErr(err error) error {
 	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 		return nil
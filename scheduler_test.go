@@ -0,0 +1,295 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/quenbyako/core"
+)
+
+func TestRunJobsPanic(t *testing.T) {
+	t.Parallel()
+
+	observed := make(chan struct{})
+
+	err := RunJobs(t.Context(),
+		func(context.Context) error { panic("boom") },
+		func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				close(observed)
+				return ctx.Err()
+			case <-time.After(time.Second):
+				return errors.New("cancellation not observed")
+			}
+		},
+	)
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+
+	if panicErr.Value != "boom" {
+		t.Errorf("expected panic value %q, got %v", "boom", panicErr.Value)
+	}
+
+	if !strings.Contains(string(panicErr.Stack), "TestRunJobsPanic") {
+		t.Errorf("expected stack to reference the panicking job, got:\n%s", panicErr.Stack)
+	}
+
+	select {
+	case <-observed:
+	default:
+		t.Error("sibling job did not observe cancellation")
+	}
+}
+
+func TestRaceJobs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("first success", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := RaceJobs(t.Context(),
+			func(context.Context) (string, error) { return "", errors.New("unavailable") },
+			func(context.Context) (string, error) { return "fast", nil },
+			func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if res != "fast" {
+			t.Errorf("expected %q, got %q", "fast", res)
+		}
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		t.Parallel()
+
+		errA, errB := errors.New("a"), errors.New("b")
+
+		_, err := RaceJobs(t.Context(),
+			func(context.Context) (int, error) { return 0, errA },
+			func(context.Context) (int, error) { return 0, errB },
+			func(context.Context) (int, error) { return 0, context.Canceled },
+		)
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("expected joined error of both failures, got %v", err)
+		}
+
+		if errors.Is(err, context.Canceled) {
+			t.Errorf("expected context errors to be suppressed, got %v", err)
+		}
+	})
+}
+
+func TestRunJobsTimeout(t *testing.T) {
+	t.Parallel()
+
+	var completed [2]bool
+
+	err := RunJobsTimeout(t.Context(), 50*time.Millisecond,
+		func(context.Context) error { completed[0] = true; return nil },
+		func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+		func(context.Context) error { completed[1] = true; return nil },
+	)
+	if !errors.Is(err, ErrJobTimeout) {
+		t.Fatalf("expected %v, got %v", ErrJobTimeout, err)
+	}
+
+	if completed != [2]bool{true, true} {
+		t.Errorf("expected other jobs to complete, got %v", completed)
+	}
+
+	t.Run("caller cancellation is suppressed", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		err := RunJobsTimeout(ctx, time.Hour, func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestRunJobsLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bound", func(t *testing.T) {
+		t.Parallel()
+
+		var active, peak, ran atomic.Int32
+
+		jobs := make([]func(context.Context) error, 12)
+		for i := range jobs {
+			jobs[i] = func(context.Context) error {
+				n := active.Add(1)
+				defer active.Add(-1)
+
+				for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+				}
+
+				ran.Add(1)
+				time.Sleep(5 * time.Millisecond)
+
+				return nil
+			}
+		}
+
+		if err := RunJobsLimit(t.Context(), 3, jobs...); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := peak.Load(); got > 3 {
+			t.Errorf("expected at most 3 concurrent jobs, got %v", got)
+		}
+
+		if got := ran.Load(); got != 12 {
+			t.Errorf("expected every job to run, got %v", got)
+		}
+	})
+
+	// first job to run holds the only slot for a while, so others are queued
+	// by the time it returns.
+	queued := func(ran *atomic.Int32, first func(context.Context) error) []func(context.Context) error {
+		jobs := make([]func(context.Context) error, 5)
+		for i := range jobs {
+			jobs[i] = func(ctx context.Context) error {
+				if ran.Add(1) != 1 {
+					return nil
+				}
+
+				time.Sleep(50 * time.Millisecond)
+
+				return first(ctx)
+			}
+		}
+
+		return jobs
+	}
+
+	t.Run("failure skips queued", func(t *testing.T) {
+		t.Parallel()
+
+		errFailed := errors.New("failed")
+
+		var ran atomic.Int32
+
+		err := RunJobsLimit(t.Context(), 1, queued(&ran, func(context.Context) error { return errFailed })...)
+		if !errors.Is(err, errFailed) {
+			t.Fatalf("expected %v, got %v", errFailed, err)
+		}
+
+		if got := ran.Load(); got != 1 {
+			t.Errorf("expected queued jobs to be skipped, got %v jobs run", got)
+		}
+	})
+
+	t.Run("cancellation skips queued", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		var ran atomic.Int32
+
+		err := RunJobsLimit(ctx, 1, queued(&ran, func(ctx context.Context) error {
+			cancel()
+
+			return ctx.Err()
+		})...)
+		if err != nil {
+			t.Fatalf("expected caller cancellation to be suppressed, got %v", err)
+		}
+
+		if got := ran.Load(); got != 1 {
+			t.Errorf("expected queued jobs to be skipped, got %v jobs run", got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		errA, errB := errors.New("a"), errors.New("b")
+
+		// both failing jobs are running before any of them fails.
+		var started sync.WaitGroup
+		started.Add(2)
+		failAfterStart := func(err error) func(context.Context) error {
+			return func(context.Context) error {
+				started.Done()
+				started.Wait()
+
+				return err
+			}
+		}
+
+		err := RunJobsLimit(t.Context(), 2,
+			failAfterStart(errA),
+			failAfterStart(errB),
+			// releases its slot at once, not blocking the failing jobs.
+			func(context.Context) error { return context.Canceled },
+		)
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("expected joined error of both failures, got %v", err)
+		}
+
+		if errors.Is(err, context.Canceled) {
+			t.Errorf("expected context errors to be suppressed, got %v", err)
+		}
+	})
+
+	for _, n := range []int{0, -1} {
+		t.Run(fmt.Sprintf("no limit %v", n), func(t *testing.T) {
+			t.Parallel()
+
+			// every job waits for all others, so it would hang with any limit.
+			const count = 8
+
+			var started sync.WaitGroup
+			started.Add(count)
+
+			all := make(chan struct{})
+			go func() { started.Wait(); close(all) }()
+
+			jobs := make([]func(context.Context) error, count)
+			for i := range jobs {
+				jobs[i] = func(context.Context) error {
+					started.Done()
+
+					select {
+					case <-all:
+						return nil
+					case <-time.After(time.Second):
+						return errors.New("jobs are not run concurrently")
+					}
+				}
+			}
+
+			if err := RunJobsLimit(t.Context(), n, jobs...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
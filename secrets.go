@@ -0,0 +1,27 @@
+package core
+
+import (
+	"context"
+
+	"github.com/quenbyako/core/secrets"
+)
+
+type ctxSecretsKey struct{}
+
+// WithSecrets returns a derived context carrying the provided
+// [secrets.Engine], so code outside of [EnvParam] lifecycle (e.g. action
+// itself) can resolve secrets via [SecretsFromContext].
+func WithSecrets(ctx context.Context, engine secrets.Engine) context.Context {
+	return context.WithValue(ctx, ctxSecretsKey{}, engine)
+}
+
+// SecretsFromContext extracts a [secrets.Engine] previously attached with
+// [WithSecrets]. Unlike other context helpers, there is no default engine:
+// when absent, nil and false are returned.
+//
+//nolint:ireturn // returns interface on intention.
+func SecretsFromContext(ctx context.Context) (secrets.Engine, bool) {
+	v, ok := ctx.Value(ctxSecretsKey{}).(secrets.Engine)
+
+	return v, ok
+}
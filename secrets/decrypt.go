@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+)
+
+type decryptingSecret struct {
+	inner   Secret
+	decrypt func(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+var _ Secret = (*decryptingSecret)(nil) //nolint:grouper // type check
+
+// NewDecryptingSecret wraps inner, holding encrypted value, decrypting it
+// with decrypt on each [Secret.Get], so plaintext is never kept in memory
+// longer than caller needs it.
+//
+//nolint:ireturn // returns interface on intention.
+func NewDecryptingSecret(inner Secret, decrypt func(ciphertext []byte) ([]byte, error)) Secret {
+	return &decryptingSecret{
+		inner:   inner,
+		decrypt: func(_ context.Context, ciphertext []byte) ([]byte, error) { return decrypt(ciphertext) },
+	}
+}
+
+// NewAESGCMSecret wraps inner, holding value encrypted with AES-GCM as nonce
+// followed by the sealed data. Key (16, 24 or 32 bytes, selecting AES-128,
+// AES-192 or AES-256) is fetched from key secret on each [Secret.Get], so it
+// may be served by another storage, e.g. KMS. Value encrypted with another key
+// fails authentication instead of returning garbage.
+//
+//nolint:ireturn // returns interface on intention.
+func NewAESGCMSecret(inner, key Secret) Secret {
+	return &decryptingSecret{
+		inner: inner,
+		decrypt: func(ctx context.Context, ciphertext []byte) ([]byte, error) {
+			k, err := key.Get(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("getting encryption key: %w", err)
+			}
+
+			return decryptAESGCM(k, ciphertext)
+		},
+	}
+}
+
+func (s *decryptingSecret) Get(ctx context.Context) ([]byte, error) {
+	ciphertext, err := s.inner.Get(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent wrapper
+	}
+
+	plaintext, err := s.decrypt(ctx, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secret: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func decryptAESGCM(key, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext is shorter than nonce")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by caller
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,99 @@
+package secrets_test
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	. "github.com/quenbyako/core/secrets"
+)
+
+// sealAESGCM encrypts plaintext in format expected by [NewAESGCMSecret].
+func sealAESGCM(t *testing.T, key, plaintext []byte) []byte {
+	t.Helper()
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil)
+}
+
+func TestAESGCMSecret(t *testing.T) {
+	key, otherKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	ciphertext := sealAESGCM(t, key, []byte("hunter2"))
+
+	t.Run("decrypts", func(t *testing.T) {
+		data, err := NewAESGCMSecret(NewPlainSecret(ciphertext), NewPlainSecret(key)).Get(t.Context())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(data) != "hunter2" {
+			t.Fatalf("expected %q, got %q", "hunter2", data)
+		}
+	})
+
+	for name, tt := range map[string]struct {
+		ciphertext, key []byte
+	}{
+		"wrong key":     {ciphertext: ciphertext, key: otherKey},
+		"invalid key":   {ciphertext: ciphertext, key: []byte("short")},
+		"tampered":      {ciphertext: append(bytes.Clone(ciphertext[:len(ciphertext)-1]), 0), key: key},
+		"short payload": {ciphertext: []byte("abc"), key: key},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := NewAESGCMSecret(NewPlainSecret(tt.ciphertext), NewPlainSecret(tt.key)).Get(t.Context())
+			if err == nil || data != nil {
+				t.Fatalf("expected error, got %q", data)
+			}
+		})
+	}
+
+	t.Run("key error", func(t *testing.T) {
+		_, err := NewAESGCMSecret(NewPlainSecret(ciphertext), NewFileSecret(nil, "../key")).Get(t.Context())
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDecryptingSecret(t *testing.T) {
+	errDecrypt := errors.New("bad ciphertext")
+
+	reverse := func(data []byte) ([]byte, error) {
+		if len(data) == 0 {
+			return nil, errDecrypt
+		}
+
+		res := bytes.Clone(data)
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+
+		return res, nil
+	}
+
+	data, err := NewDecryptingSecret(NewPlainSecret([]byte("2retnuh")), reverse).Get(t.Context())
+	if err != nil || string(data) != "hunter2" {
+		t.Fatalf("expected %q, got %q, %v", "hunter2", data, err)
+	}
+
+	if _, err := NewDecryptingSecret(NewEmptySecret(), reverse).Get(t.Context()); !errors.Is(err, errDecrypt) {
+		t.Fatalf("expected %v, got %v", errDecrypt, err)
+	}
+}
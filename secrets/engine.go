@@ -15,6 +15,22 @@ type Engine interface {
 	GetSecret(ctx context.Context, addr string) (Secret, error)
 }
 
+// GetSecretString looks up secret at addr in e, returning its value as a
+// string.
+func GetSecretString(ctx context.Context, e Engine, addr string) (string, error) {
+	secret, err := e.GetSecret(ctx, addr)
+	if err != nil {
+		return "", err //nolint:wrapcheck // engine errors as is
+	}
+
+	data, err := secret.Get(ctx)
+	if err != nil {
+		return "", err //nolint:wrapcheck // secret errors as is
+	}
+
+	return string(data), nil
+}
+
 type unsetStorage struct {
 	name string
 }
@@ -39,6 +55,24 @@ func (u *unsetStorage) Close() error {
 	return nil
 }
 
+type noopEngine struct{}
+
+var _ Engine = noopEngine{} //nolint:grouper // type check
+
+// NewNoopEngine returns an [Engine] resolving every address to
+// [NewEmptySecret]. Unlike [NewUnsetStorage], lookups never fail, so it fits
+// to disable secrets entirely, e.g. in tests.
+//
+//nolint:ireturn // returns interface on intention.
+func NewNoopEngine() Engine { return noopEngine{} }
+
+// GetSecret always returns an empty [Secret].
+//
+//nolint:ireturn // returns interface on intention.
+func (noopEngine) GetSecret(context.Context, string) (Secret, error) { return NewEmptySecret(), nil }
+
+func (noopEngine) Close() error { return nil }
+
 type constantStorage struct {
 	data []byte
 }
@@ -0,0 +1,45 @@
+package secrets_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/quenbyako/core/secrets"
+)
+
+func TestNoopEngine(t *testing.T) {
+	engine := NewNoopEngine()
+
+	for _, addr := range []string{"", "vault:db/password", "app/db"} {
+		secret, err := engine.GetSecret(t.Context(), addr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", addr, err)
+		}
+
+		if data, err := secret.Get(t.Context()); err != nil || data != nil {
+			t.Fatalf("%q: expected empty secret, got %q, %v", addr, data, err)
+		}
+	}
+
+	if err := engine.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetSecretString(t *testing.T) {
+	value, err := GetSecretString(t.Context(), NewConstantStorage([]byte("hunter2\n")), "db/password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value != "hunter2\n" {
+		t.Fatalf("expected %q, got %q", "hunter2\n", value)
+	}
+
+	t.Run("engine error", func(t *testing.T) {
+		_, err := GetSecretString(t.Context(), NewPolicyEngine(NewNoopEngine(), func(string) bool { return false }), "db")
+		if !errors.Is(err, ErrPermissionDenied) {
+			t.Fatalf("expected %v, got %v", ErrPermissionDenied, err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package secrets
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
@@ -14,4 +15,20 @@ var (
 	// ErrEngineNotConfigured signals that a higher-level component attempted
 	// to use an Engine that was not injected / initialized.
 	ErrEngineNotConfigured = errors.New("secrets engine not configured")
+	// ErrPermissionDenied reports that access to the secret address is
+	// forbidden by a policy. See [PermissionDeniedError].
+	ErrPermissionDenied = errors.New("permission denied")
 )
+
+// PermissionDeniedError is returned by the engine built with
+// [NewPolicyEngine] for addresses rejected by the policy. It matches
+// [ErrPermissionDenied] via [errors.Is].
+type PermissionDeniedError struct {
+	Addr string
+}
+
+func (e *PermissionDeniedError) Error() string {
+	return fmt.Sprintf("access to secret %q: %v", e.Addr, ErrPermissionDenied)
+}
+
+func (e *PermissionDeniedError) Unwrap() error { return ErrPermissionDenied }
@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+type expiringSecret struct {
+	inner Secret
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu        sync.Mutex
+	data      []byte
+	fetchedAt time.Time
+	cached    bool
+}
+
+var _ Secret = (*expiringSecret)(nil) //nolint:grouper // type check
+
+// NewExpiringSecret wraps inner, caching value of the first [Secret.Get] for
+// ttl, after which it's fetched from inner again, e.g. to pick up rotated
+// credentials. Failed fetches are not cached: next Get retries. Concurrent
+// Get calls are serialized, so inner is fetched at most once per expiry.
+//
+//nolint:ireturn // returns interface on intention.
+func NewExpiringSecret(inner Secret, ttl time.Duration) Secret {
+	return newExpiringSecret(inner, ttl, time.Now)
+}
+
+func newExpiringSecret(inner Secret, ttl time.Duration, now func() time.Time) *expiringSecret {
+	return &expiringSecret{inner: inner, ttl: ttl, now: now}
+}
+
+func (s *expiringSecret) Get(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached && s.now().Sub(s.fetchedAt) < s.ttl {
+		return bytes.Clone(s.data), nil
+	}
+
+	data, err := s.inner.Get(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent wrapper
+	}
+
+	s.data, s.fetchedAt, s.cached = bytes.Clone(data), s.now(), true
+
+	return data, nil
+}
@@ -0,0 +1,98 @@
+package secrets_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/quenbyako/core/secrets"
+)
+
+// sequenceSecret returns its values one by one, failing with err when it's
+// set.
+type sequenceSecret struct {
+	values []string
+	calls  int
+	err    error
+}
+
+func (s *sequenceSecret) Get(context.Context) ([]byte, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return []byte(s.values[min(s.calls, len(s.values))-1]), nil
+}
+
+func TestExpiringSecret(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := func() time.Time { return clock }
+
+	inner := &sequenceSecret{values: []string{"first", "rotated"}}
+	secret := NewExpiringSecretWithClock(inner, time.Minute, now)
+
+	get := func(t *testing.T, want string) {
+		t.Helper()
+
+		data, err := secret.Get(t.Context())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(data) != want {
+			t.Fatalf("expected %q, got %q", want, data)
+		}
+	}
+
+	get(t, "first")
+
+	clock = clock.Add(59 * time.Second)
+	get(t, "first")
+
+	if inner.calls != 1 {
+		t.Fatalf("expected cached value, inner called %v times", inner.calls)
+	}
+
+	clock = clock.Add(time.Second)
+	get(t, "rotated")
+
+	if inner.calls != 2 {
+		t.Fatalf("expected re-fetch after expiry, inner called %v times", inner.calls)
+	}
+
+	t.Run("error is not cached", func(t *testing.T) {
+		errFetch := errors.New("backend unavailable")
+
+		clock = clock.Add(time.Minute)
+		inner.err = errFetch
+
+		if _, err := secret.Get(t.Context()); !errors.Is(err, errFetch) {
+			t.Fatalf("expected %v, got %v", errFetch, err)
+		}
+
+		inner.err = nil
+		get(t, "rotated")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		inner := &sequenceSecret{values: []string{"value"}}
+		secret := NewExpiringSecret(inner, time.Hour)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				if data, err := secret.Get(t.Context()); err != nil || string(data) != "value" {
+					t.Errorf("unexpected result: %q, %v", data, err)
+				}
+			})
+		}
+		wg.Wait()
+
+		if inner.calls != 1 {
+			t.Errorf("expected single fetch, got %v", inner.calls)
+		}
+	})
+}
@@ -0,0 +1,8 @@
+package secrets
+
+import "time"
+
+//nolint:gochecknoglobals // exported for tests only.
+var NewExpiringSecretWithClock = func(inner Secret, ttl time.Duration, now func() time.Time) Secret {
+	return newExpiringSecret(inner, ttl, now)
+}
@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type fallbackEngine struct {
+	engines []Engine
+	strict  bool
+}
+
+var _ Engine = (*fallbackEngine)(nil) //nolint:grouper // type check
+
+// NewFallbackEngine returns an [Engine] looking secrets up in engines in
+// order, e.g. "vault, then file, then env", returning the first found one.
+// Failing engines are skipped as well as missing ones: lookup fails with
+// [ErrSecretNotFound] only if every engine misses, otherwise failures are
+// joined. See [NewStrictFallbackEngine] to stop on the first failure instead.
+//
+//nolint:ireturn // returns interface on intention.
+func NewFallbackEngine(engines ...Engine) Engine { return &fallbackEngine{engines: engines} }
+
+// NewStrictFallbackEngine works as [NewFallbackEngine], but only falls
+// through on [ErrSecretNotFound]: any other error of an engine stops lookup,
+// so outage of primary storage doesn't silently serve secrets of a secondary
+// one.
+//
+//nolint:ireturn // returns interface on intention.
+func NewStrictFallbackEngine(engines ...Engine) Engine {
+	return &fallbackEngine{engines: engines, strict: true}
+}
+
+//nolint:ireturn // returns interface on intention.
+func (e *fallbackEngine) GetSecret(ctx context.Context, addr string) (Secret, error) {
+	var errs []error
+
+	for i, engine := range e.engines {
+		secret, err := engine.GetSecret(ctx, addr)
+		switch {
+		case err == nil:
+			return secret, nil
+		case errors.Is(err, ErrSecretNotFound):
+			continue
+		case e.strict:
+			return nil, fmt.Errorf("engine #%v: %w", i, err)
+		default:
+			errs = append(errs, fmt.Errorf("engine #%v: %w", i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return nil, ErrSecretNotFound
+}
+
+func (e *fallbackEngine) Close() error {
+	var errs []error
+	for _, engine := range e.engines {
+		if err := engine.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
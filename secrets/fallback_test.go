@@ -0,0 +1,87 @@
+package secrets_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/quenbyako/core/secrets"
+	"github.com/quenbyako/core/secrets/secretstest"
+)
+
+func TestFallbackEngine(t *testing.T) {
+	errOutage := errors.New("storage is down")
+
+	primary := secretstest.NewFakeEngine(
+		secretstest.WithValue("db", []byte("primary")),
+		secretstest.WithError("api", errOutage),
+	)
+	secondary := secretstest.NewFakeEngine(
+		secretstest.WithValue("db", []byte("secondary")),
+		secretstest.WithValue("api", []byte("secondary")),
+		secretstest.WithValue("cache", []byte("secondary")),
+	)
+
+	for name, tt := range map[string]struct {
+		engine  Engine
+		addr    string
+		want    string
+		wantErr error
+	}{
+		"first wins":          {engine: NewFallbackEngine(primary, secondary), addr: "db", want: "primary"},
+		"order matters":       {engine: NewFallbackEngine(secondary, primary), addr: "db", want: "secondary"},
+		"falls through miss":  {engine: NewFallbackEngine(primary, secondary), addr: "cache", want: "secondary"},
+		"falls through error": {engine: NewFallbackEngine(primary, secondary), addr: "api", want: "secondary"},
+		"all miss":            {engine: NewFallbackEngine(primary, secondary), addr: "missing", wantErr: ErrSecretNotFound},
+		"no engines":          {engine: NewFallbackEngine(), addr: "db", wantErr: ErrSecretNotFound},
+		"strict falls through miss": {
+			engine: NewStrictFallbackEngine(primary, secondary), addr: "cache", want: "secondary",
+		},
+		"strict stops on error": {
+			engine: NewStrictFallbackEngine(primary, secondary), addr: "api", wantErr: errOutage,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := GetSecretString(t.Context(), tt.engine, tt.addr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if data != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, data)
+			}
+		})
+	}
+
+	t.Run("errors are joined", func(t *testing.T) {
+		errOther := errors.New("permission denied")
+
+		engine := NewFallbackEngine(
+			secretstest.NewFakeEngine(secretstest.WithError("db", errOutage)),
+			secretstest.NewFakeEngine(),
+			secretstest.NewFakeEngine(secretstest.WithError("db", errOther)),
+		)
+
+		_, err := engine.GetSecret(t.Context(), "db")
+		if !errors.Is(err, errOutage) || !errors.Is(err, errOther) {
+			t.Fatalf("expected both errors, got %v", err)
+		}
+
+		if errors.Is(err, ErrSecretNotFound) {
+			t.Fatalf("failures must not be reported as missing secret: %v", err)
+		}
+	})
+
+	t.Run("close closes all", func(t *testing.T) {
+		first, second := secretstest.NewFakeEngine(), secretstest.NewFakeEngine()
+
+		if err := NewFallbackEngine(first, second).Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, engine := range []Engine{first, second} {
+			if _, err := engine.GetSecret(t.Context(), "db"); !errors.Is(err, ErrEngineNotConfigured) {
+				t.Errorf("engine is not closed: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type jsonFieldSecret struct {
+	inner   Secret
+	pointer string
+}
+
+var _ Secret = (*jsonFieldSecret)(nil) //nolint:grouper // type check
+
+// NewJSONFieldSecret wraps inner, holding JSON document, extracting node at
+// RFC 6901 JSON Pointer (e.g. "/data/password") on each [Secret.Get]. Raw JSON
+// of the selected node is returned, except strings, which are unquoted. Empty
+// pointer selects the whole document. Missing node is reported as
+// [ErrSecretNotFound].
+//
+//nolint:ireturn // returns interface on intention.
+func NewJSONFieldSecret(inner Secret, pointer string) Secret {
+	return &jsonFieldSecret{inner: inner, pointer: pointer}
+}
+
+func (s *jsonFieldSecret) Get(ctx context.Context) ([]byte, error) {
+	data, err := s.inner.Get(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent wrapper
+	}
+
+	node, err := jsonPointer(data, s.pointer)
+	if err != nil {
+		return nil, fmt.Errorf("extracting %q from secret: %w", s.pointer, err)
+	}
+
+	if bytes.HasPrefix(node, []byte(`"`)) {
+		var str string
+		if err := json.Unmarshal(node, &str); err != nil {
+			return nil, fmt.Errorf("extracting %q from secret: %w", s.pointer, err)
+		}
+
+		return []byte(str), nil
+	}
+
+	return node, nil
+}
+
+// jsonPointer returns raw JSON of node at pointer in data.
+func jsonPointer(data []byte, pointer string) (json.RawMessage, error) {
+	var node json.RawMessage
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+
+	if pointer == "" {
+		return node, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.New(`JSON pointer must start with "/"`)
+	}
+
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+
+	for token := range strings.SplitSeq(pointer[1:], "/") {
+		token = unescape.Replace(token)
+
+		var (
+			next json.RawMessage
+			ok   bool
+		)
+
+		switch node[0] {
+		case '{':
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(node, &object); err != nil {
+				return nil, fmt.Errorf("parsing JSON: %w", err)
+			}
+
+			next, ok = object[token]
+
+		case '[':
+			var array []json.RawMessage
+			if err := json.Unmarshal(node, &array); err != nil {
+				return nil, fmt.Errorf("parsing JSON: %w", err)
+			}
+
+			// leading zeros and signs are not allowed by RFC 6901.
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(array) && strconv.Itoa(i) == token {
+				next, ok = array[i], true
+			}
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("%w: no JSON node %q", ErrSecretNotFound, token)
+		}
+
+		node = next
+	}
+
+	return node, nil
+}
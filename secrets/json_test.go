@@ -0,0 +1,63 @@
+package secrets_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/quenbyako/core/secrets"
+)
+
+func TestJSONFieldSecret(t *testing.T) {
+	doc := NewPlainSecret([]byte(`{
+		"data": {"password": "hunter\"2", "port": 5432, "hosts": ["db-1", "db-2"], "a/b": {"~c": true}}
+	}`))
+
+	for name, tt := range map[string]struct {
+		pointer string
+		want    string
+	}{
+		"nested string": {pointer: "/data/password", want: `hunter"2`},
+		"number":        {pointer: "/data/port", want: "5432"},
+		"array item":    {pointer: "/data/hosts/1", want: "db-2"},
+		"object":        {pointer: "/data/hosts", want: `["db-1", "db-2"]`},
+		"escaped":       {pointer: "/data/a~1b/~0c", want: "true"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := NewJSONFieldSecret(doc, tt.pointer).Get(t.Context())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(data) != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, data)
+			}
+		})
+	}
+
+	for name, pointer := range map[string]string{
+		"missing field":     "/data/username",
+		"missing index":     "/data/hosts/2",
+		"leading zero":      "/data/hosts/01",
+		"field of a string": "/data/password/value",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewJSONFieldSecret(doc, pointer).Get(t.Context()); !errors.Is(err, ErrSecretNotFound) {
+				t.Fatalf("expected %v, got %v", ErrSecretNotFound, err)
+			}
+		})
+	}
+
+	for name, tt := range map[string]struct {
+		secret  Secret
+		pointer string
+	}{
+		"invalid pointer": {secret: doc, pointer: "data"},
+		"invalid JSON":    {secret: NewPlainSecret([]byte("password")), pointer: "/data"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewJSONFieldSecret(tt.secret, tt.pointer).Get(t.Context()); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
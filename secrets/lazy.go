@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// MaxLazySecretSize limits secrets read by [NewFileSecret] and
+// [NewReaderSecret], so misconfigured source (e.g. a device file) can't
+// exhaust memory.
+const MaxLazySecretSize = 16 << 20
+
+type readerSecret struct {
+	open func() (io.ReadCloser, error)
+}
+
+var _ Secret = (*readerSecret)(nil) //nolint:grouper // type check
+
+// NewReaderSecret returns a Secret reading the stream opened by open on each
+// [Secret.Get] call, so secret material is not held in memory until used.
+// Stream is closed after reading. Secrets larger than [MaxLazySecretSize] are
+// rejected.
+//
+//nolint:ireturn // returns interface on intention.
+func NewReaderSecret(open func() (io.ReadCloser, error)) Secret {
+	return &readerSecret{open: open}
+}
+
+func (s *readerSecret) Get(ctx context.Context) (data []byte, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // context error as is
+	}
+
+	r, err := s.open()
+	if err != nil {
+		return nil, fmt.Errorf("opening secret: %w", err)
+	}
+	defer func() {
+		if closeErr := r.Close(); closeErr != nil && err == nil {
+			data, err = nil, fmt.Errorf("closing secret: %w", closeErr)
+		}
+	}()
+
+	return readLimited(r)
+}
+
+// NewFileSecret returns a Secret reading file at path of fsys on each
+// [Secret.Get] call. Missing file is reported as [ErrSecretNotFound]. Files
+// larger than [MaxLazySecretSize] are rejected.
+//
+//nolint:ireturn // returns interface on intention.
+func NewFileSecret(fsys fs.FS, path string) Secret {
+	return NewReaderSecret(func() (io.ReadCloser, error) {
+		if !fs.ValidPath(path) {
+			return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrInvalid}
+		}
+
+		f, err := fsys.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %w", ErrSecretNotFound, err)
+		}
+
+		return f, err //nolint:wrapcheck // wrapped by reader secret
+	})
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxLazySecretSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading secret: %w", err)
+	}
+
+	if len(data) > MaxLazySecretSize {
+		return nil, fmt.Errorf("secret exceeds %v bytes", MaxLazySecretSize)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,135 @@
+package secrets_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/quenbyako/core/secrets"
+)
+
+func TestFileSecret(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tls/bundle.pem": {Data: []byte("-----BEGIN CERTIFICATE-----")},
+	}
+
+	t.Run("present file", func(t *testing.T) {
+		secret := NewFileSecret(fsys, "tls/bundle.pem")
+
+		data, err := secret.Get(t.Context())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(data) != "-----BEGIN CERTIFICATE-----" {
+			t.Fatalf("unexpected data %q", data)
+		}
+
+		// read on demand: changes are picked up.
+		fsys["tls/bundle.pem"] = &fstest.MapFile{Data: []byte("rotated")}
+
+		if data, _ := secret.Get(t.Context()); string(data) != "rotated" {
+			t.Errorf("expected rotated data, got %q", data)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewFileSecret(fsys, "tls/missing.pem").Get(t.Context())
+		if !errors.Is(err, ErrSecretNotFound) || !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected %v, got %v", ErrSecretNotFound, err)
+		}
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		if _, err := NewFileSecret(fsys, "../etc/passwd").Get(t.Context()); !errors.Is(err, fs.ErrInvalid) {
+			t.Fatalf("expected %v, got %v", fs.ErrInvalid, err)
+		}
+	})
+}
+
+// failingReader fails reading or closing with the given errors.
+type failingReader struct {
+	io.Reader
+
+	readErr, closeErr error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.readErr != nil {
+		return 0, r.readErr
+	}
+
+	return r.Reader.Read(p)
+}
+
+func (r *failingReader) Close() error { return r.closeErr }
+
+func TestReaderSecret(t *testing.T) {
+	errSource := errors.New("source failure")
+
+	for name, tt := range map[string]struct {
+		open    func() (io.ReadCloser, error)
+		want    string
+		wantErr error
+	}{
+		"success": {
+			open: func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("value")), nil },
+			want: "value",
+		},
+		"open error": {
+			open:    func() (io.ReadCloser, error) { return nil, errSource },
+			wantErr: errSource,
+		},
+		"read error": {
+			open:    func() (io.ReadCloser, error) { return &failingReader{readErr: errSource}, nil },
+			wantErr: errSource,
+		},
+		"close error": {
+			open: func() (io.ReadCloser, error) {
+				return &failingReader{Reader: strings.NewReader("value"), closeErr: errSource}, nil
+			},
+			wantErr: errSource,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := NewReaderSecret(tt.open).Get(t.Context())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if string(data) != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, data)
+			}
+		})
+	}
+
+	t.Run("too large", func(t *testing.T) {
+		secret := NewReaderSecret(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(make([]byte, MaxLazySecretSize+1))), nil
+		})
+
+		if _, err := secret.Get(t.Context()); err == nil || !strings.Contains(err.Error(), "secret exceeds") {
+			t.Fatalf("expected size error, got %v", err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		secret := NewReaderSecret(func() (io.ReadCloser, error) {
+			t.Error("source must not be opened")
+
+			return nil, errSource
+		})
+
+		if _, err := secret.Get(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+}
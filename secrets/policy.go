@@ -0,0 +1,34 @@
+package secrets
+
+import (
+	"context"
+)
+
+type policyEngine struct {
+	inner Engine
+	allow func(addr string) bool
+}
+
+var _ Engine = (*policyEngine)(nil) //nolint:grouper // type check
+
+// NewPolicyEngine wraps inner, rejecting lookups of addresses for which allow
+// returns false with a [*PermissionDeniedError]. Rejected lookups never reach
+// the inner engine. Close is forwarded to inner.
+//
+//nolint:ireturn // returns interface on intention.
+func NewPolicyEngine(inner Engine, allow func(addr string) bool) Engine {
+	return &policyEngine{inner: inner, allow: allow}
+}
+
+//nolint:ireturn // returns interface on intention.
+func (p *policyEngine) GetSecret(ctx context.Context, addr string) (Secret, error) {
+	if !p.allow(addr) {
+		return nil, &PermissionDeniedError{Addr: addr}
+	}
+
+	return p.inner.GetSecret(ctx, addr) //nolint:wrapcheck // transparent decorator
+}
+
+func (p *policyEngine) Close() error {
+	return p.inner.Close() //nolint:wrapcheck // transparent decorator
+}
@@ -0,0 +1,47 @@
+package secrets_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/quenbyako/core/secrets"
+)
+
+func TestPolicyEngine(t *testing.T) {
+	engine := NewPolicyEngine(NewConstantStorage([]byte("value")), func(addr string) bool {
+		return strings.HasPrefix(addr, "app/")
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		secret, err := engine.GetSecret(t.Context(), "app/db")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		data, err := secret.Get(t.Context())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if string(data) != "value" {
+			t.Fatalf("expected %q, got %q", "value", data)
+		}
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		_, err := engine.GetSecret(t.Context(), "other/db")
+		if !errors.Is(err, ErrPermissionDenied) {
+			t.Fatalf("expected %v, got %v", ErrPermissionDenied, err)
+		}
+
+		var denied *PermissionDeniedError
+		if !errors.As(err, &denied) {
+			t.Fatalf("expected %T, got %T", denied, err)
+		}
+
+		if denied.Addr != "other/db" {
+			t.Fatalf("expected address %q, got %q", "other/db", denied.Addr)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package secrets
 import (
 	"bytes"
 	"context"
+	"unicode"
 )
 
 // Secret represents a retrievable opaque byte slice. Implementations may
@@ -37,3 +38,24 @@ func (s *plainSecret) Get(context.Context) ([]byte, error) { return bytes.Clone(
 //
 //nolint:ireturn // returns interface on intention.
 func NewPlainSecret(data []byte) Secret { return &plainSecret{data: data} }
+
+type trimmedSecret struct {
+	inner Secret
+}
+
+var _ Secret = (*trimmedSecret)(nil) //nolint:grouper // type check
+
+// NewTrimmedSecret wraps inner, trimming trailing whitespace (e.g. newline
+// left by editor in secret file) from its value on each [Secret.Get].
+//
+//nolint:ireturn // returns interface on intention.
+func NewTrimmedSecret(inner Secret) Secret { return &trimmedSecret{inner: inner} }
+
+func (s *trimmedSecret) Get(ctx context.Context) ([]byte, error) {
+	data, err := s.inner.Get(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // transparent wrapper
+	}
+
+	return bytes.TrimRightFunc(data, unicode.IsSpace), nil
+}
@@ -0,0 +1,34 @@
+package secrets_test
+
+import (
+	"testing"
+
+	. "github.com/quenbyako/core/secrets"
+)
+
+func TestTrimmedSecret(t *testing.T) {
+	for _, tt := range []struct {
+		value, want string
+	}{
+		{"hunter2", "hunter2"},
+		{"hunter2\n", "hunter2"},
+		{"hunter2 \r\n\t", "hunter2"},
+		{"  hunter2", "  hunter2"},
+	} {
+		data, err := NewTrimmedSecret(NewPlainSecret([]byte(tt.value))).Get(t.Context())
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.value, err)
+		}
+
+		if string(data) != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.value, tt.want, data)
+		}
+	}
+
+	t.Run("inner error", func(t *testing.T) {
+		_, err := NewTrimmedSecret(NewFileSecret(nil, "../invalid")).Get(t.Context())
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
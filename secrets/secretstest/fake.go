@@ -0,0 +1,97 @@
+// Package secretstest provides in-process [secrets.Engine] implementations for
+// testing timeout, retry and error handling of secret consumers.
+package secretstest
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/quenbyako/core/secrets"
+)
+
+type fakeEntry struct {
+	value []byte
+	err   error
+	delay time.Duration
+}
+
+type fakeEngine struct {
+	entries map[string]fakeEntry
+	closed  atomic.Bool
+}
+
+var _ secrets.Engine = (*fakeEngine)(nil) //nolint:grouper // type check
+
+type FakeOption func(*fakeEngine)
+
+// WithValue makes lookups of addr succeed with value.
+func WithValue(addr string, value []byte) FakeOption {
+	return func(e *fakeEngine) { e.update(addr, func(f *fakeEntry) { f.value, f.err = value, nil }) }
+}
+
+// WithError makes lookups of addr fail with err.
+func WithError(addr string, err error) FakeOption {
+	return func(e *fakeEngine) { e.update(addr, func(f *fakeEntry) { f.err = err }) }
+}
+
+// WithDelay delays lookups of addr by d, or until the lookup context is
+// cancelled, whichever happens first.
+func WithDelay(addr string, d time.Duration) FakeOption {
+	return func(e *fakeEngine) { e.update(addr, func(f *fakeEntry) { f.delay = d }) }
+}
+
+// NewFakeEngine returns an engine serving configured addresses. Lookups of
+// unknown addresses fail with [secrets.ErrSecretNotFound], lookups after Close
+// fail with [secrets.ErrEngineNotConfigured].
+//
+//nolint:ireturn // returns interface on intention.
+func NewFakeEngine(opts ...FakeOption) secrets.Engine {
+	e := &fakeEngine{entries: make(map[string]fakeEntry)}
+	for _, o := range opts {
+		o(e)
+	}
+
+	return e
+}
+
+func (e *fakeEngine) update(addr string, f func(*fakeEntry)) {
+	entry := e.entries[addr]
+	f(&entry)
+	e.entries[addr] = entry
+}
+
+//nolint:ireturn // returns interface on intention.
+func (e *fakeEngine) GetSecret(ctx context.Context, addr string) (secrets.Secret, error) {
+	if e.closed.Load() {
+		return nil, secrets.ErrEngineNotConfigured
+	}
+
+	entry, ok := e.entries[addr]
+	if !ok {
+		return nil, secrets.ErrSecretNotFound
+	}
+
+	if entry.delay > 0 {
+		timer := time.NewTimer(entry.delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err() //nolint:wrapcheck // context error as is
+		case <-timer.C:
+		}
+	}
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	return secrets.NewPlainSecret(entry.value), nil
+}
+
+func (e *fakeEngine) Close() error {
+	e.closed.Store(true)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package secretstest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quenbyako/core/secrets"
+	. "github.com/quenbyako/core/secrets/secretstest"
+)
+
+func TestFakeEngine(t *testing.T) {
+	t.Parallel()
+
+	errBackend := errors.New("backend unavailable")
+
+	engine := NewFakeEngine(
+		WithValue("db/password", []byte("hunter2")),
+		WithError("db/broken", errBackend),
+		WithValue("db/slow", []byte("eventually")),
+		WithDelay("db/slow", time.Hour),
+	)
+
+	t.Run("value", func(t *testing.T) {
+		t.Parallel()
+
+		secret, err := engine.GetSecret(t.Context(), "db/password")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if data, _ := secret.Get(t.Context()); string(data) != "hunter2" {
+			t.Errorf("expected %q, got %q", "hunter2", data)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := engine.GetSecret(t.Context(), "db/broken"); !errors.Is(err, errBackend) {
+			t.Errorf("expected %v, got %v", errBackend, err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := engine.GetSecret(t.Context(), "db/unknown"); !errors.Is(err, secrets.ErrSecretNotFound) {
+			t.Errorf("expected %v, got %v", secrets.ErrSecretNotFound, err)
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		_, err := engine.GetSecret(ctx, "db/slow")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("lookup didn't return promptly after cancellation: %v", elapsed)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/quenbyako/core"
+	"github.com/quenbyako/core/secrets"
+)
+
+func TestSecretsFromContext(t *testing.T) {
+	if engine, ok := SecretsFromContext(context.Background()); ok || engine != nil {
+		t.Fatalf("expected no engine, got %v", engine)
+	}
+
+	want := secrets.NewNoopEngine()
+
+	engine, ok := SecretsFromContext(WithSecrets(context.Background(), want))
+	if !ok || engine != want {
+		t.Fatalf("expected %v, got %v", want, engine)
+	}
+}
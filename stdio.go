@@ -17,11 +17,13 @@ type Pipeline struct {
 	stdout     io.Writer
 	stderr     io.Writer
 	isPipeline bool
+	stderrTTY  bool
 }
 
 // PipelineFromFiles builds a Pipeline from explicit [*os.File] handles (nil
 // values are replaced with process defaults). The IsPipeline bit is derived
-// by inspecting stdin's mode to detect non-interactive usage.
+// by inspecting stdin's mode to detect non-interactive usage, the same check
+// on stderr backs [Pipeline.StderrIsTerminal].
 func PipelineFromFiles(stdin, stdout, stderr *os.File) Pipeline {
 	if stdin == nil {
 		stdin = os.Stdin
@@ -40,9 +42,20 @@ func PipelineFromFiles(stdin, stdout, stderr *os.File) Pipeline {
 		stdout:     stdout,
 		stderr:     stderr,
 		isPipeline: isPipeline(stdin),
+		stderrTTY:  isTerminal(stderr),
 	}
 }
 
+// PipelineFromFilesForced is like [PipelineFromFiles], but declares pipeline
+// mode explicitly instead of detecting it, e.g. from a --no-interactive flag
+// or in CI environments, where stdin mode is misleading.
+func PipelineFromFilesForced(stdin, stdout, stderr *os.File, isPipeline bool) Pipeline {
+	p := PipelineFromFiles(stdin, stdout, stderr)
+	p.isPipeline = isPipeline
+
+	return p
+}
+
 // WithPipelines stores a [Pipeline] in a derived context for later retrieval.
 // Use [PipelinesFromContext] to extract it; if absent, a cached default is
 // provided.
@@ -75,6 +88,10 @@ func (p Pipeline) Stderr() io.Writer { return p.stderr }
 // (pipe/file). This is useful for adapting behavior (e.g., buffered reads).
 func (p Pipeline) IsPipeline() bool { return p.isPipeline }
 
+// StderrIsTerminal reports whether stderr appears to be an interactive
+// terminal, e.g. to choose human-readable log format.
+func (p Pipeline) StderrIsTerminal() bool { return p.stderrTTY }
+
 func isPipeline(in *os.File) bool {
 	stat, err := in.Stat()
 	if err != nil {
@@ -83,3 +100,12 @@ func isPipeline(in *os.File) bool {
 
 	return (stat.Mode() & os.ModeCharDevice) == 0
 }
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return (stat.Mode() & os.ModeCharDevice) != 0
+}
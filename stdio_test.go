@@ -0,0 +1,45 @@
+package core_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/quenbyako/core"
+)
+
+func TestPipelineFromFilesForced(t *testing.T) {
+	// pipe is never a char device, so it's detected as pipeline.
+	pipe, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = pipe.Close(); _ = w.Close() })
+
+	// null device is a char device, so it's detected as interactive.
+	null, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = null.Close() })
+
+	for _, tt := range []struct {
+		name  string
+		stdin *os.File
+		force bool
+	}{
+		{name: "detected pipeline, forced interactive", stdin: pipe, force: false},
+		{name: "detected interactive, forced pipeline", stdin: null, force: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if detected := PipelineFromFiles(tt.stdin, nil, nil).IsPipeline(); detected == tt.force {
+				t.Fatalf("expected detected value %v to differ from forced one", detected)
+			}
+
+			if got := PipelineFromFilesForced(tt.stdin, nil, nil, tt.force).IsPipeline(); got != tt.force {
+				t.Errorf("expected IsPipeline() to be %v, got %v", tt.force, got)
+			}
+		})
+	}
+}
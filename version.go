@@ -3,7 +3,14 @@ package core
 import (
 	"context"
 	"crypto/sha1" //nolint:gosec // this is a git hash algorithm
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/mod/semver"
@@ -35,6 +42,15 @@ const (
 	DefaultDate    = "unknown"
 
 	DefaultDateFormat = time.RFC3339
+	// UnixDateFormat is a pseudo layout for [NewVersionWithDateFormat],
+	// parsing build date as Unix timestamp in seconds (e.g. "$(date +%s)").
+	UnixDateFormat = "unix"
+
+	// MinShortCommitLength is the minimal length (in hex digits) of
+	// abbreviated commit hash accepted by [NewVersion], same as git default.
+	MinShortCommitLength = 7
+
+	hexDigits = "0123456789abcdef"
 )
 
 // AppVersion represents build-time version metadata including semantic
@@ -47,8 +63,10 @@ type AppVersion struct {
 	versionRaw   string
 	commitRaw    string
 	dateRaw      string
+	dateLayout   string
 	version      string
-	commit       [sha1.Size]byte
+	commit       []byte
+	commitHex    string
 	versionValid bool
 	commitValid  bool
 	dateValid    bool
@@ -65,6 +83,18 @@ type AppVersion struct {
 // is correct or not. Caller may use this info to warn user that version info is
 // invalid.
 func NewVersion(versionRaw, commitRaw, dateRaw string) AppVersion {
+	return NewVersionWithDateFormat(versionRaw, commitRaw, dateRaw, DefaultDateFormat)
+}
+
+// NewVersionWithDateFormat behaves like [NewVersion], but parses build date
+// with the given [time.Parse] layout, or as Unix seconds with
+// [UnixDateFormat]. Empty layout means [DefaultDateFormat]. The same layout is
+// used to render the date in [AppVersion.String].
+func NewVersionWithDateFormat(versionRaw, commitRaw, dateRaw, layout string) AppVersion {
+	if layout == "" {
+		layout = DefaultDateFormat
+	}
+
 	if versionRaw == "" {
 		versionRaw = DefaultVersion
 	}
@@ -78,17 +108,19 @@ func NewVersion(versionRaw, commitRaw, dateRaw string) AppVersion {
 	}
 
 	version, versionValid := buildVersion(versionRaw)
-	commit, commitHashValid := buildCommitHash(commitRaw)
-	date, dateValid := buildDate(dateRaw)
+	commit, commitHex, commitHashValid := buildCommitHash(commitRaw)
+	date, dateValid := buildDate(dateRaw, layout)
 
 	return AppVersion{
 		versionRaw: versionRaw,
 		commitRaw:  commitRaw,
 		dateRaw:    dateRaw,
+		dateLayout: layout,
 
-		version: version,
-		commit:  commit,
-		date:    date,
+		version:   version,
+		commit:    commit,
+		commitHex: commitHex,
+		date:      date,
 
 		versionValid: versionValid,
 		commitValid:  commitHashValid,
@@ -96,6 +128,41 @@ func NewVersion(versionRaw, commitRaw, dateRaw string) AppVersion {
 	}
 }
 
+// VersionFromBuildInfo builds version from the build info embedded by the Go
+// toolchain: main module version, "vcs.revision" and "vcs.time" settings. It's
+// useful when version info is not passed through -ldflags.
+//
+// Missing components (e.g. with go run, or builds without VCS stamping) fall
+// back to [DefaultVersion], [DefaultCommit] and [DefaultDate].
+func VersionFromBuildInfo() AppVersion {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return defaultVersion()
+	}
+
+	return versionFromBuildInfo(info)
+}
+
+func versionFromBuildInfo(info *debug.BuildInfo) AppVersion {
+	var version, commit, date string
+
+	// "(devel)" is reported for binaries built from the working tree.
+	if v := info.Main.Version; v != "(devel)" {
+		version = v
+	}
+
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			commit = s.Value
+		case "vcs.time":
+			date = s.Value
+		}
+	}
+
+	return NewVersion(version, commit, date)
+}
+
 // Valid reports whether version, commit hash and build date were all parsed
 // successfully.
 func (v AppVersion) Valid() bool {
@@ -106,8 +173,36 @@ func (v AppVersion) Valid() bool {
 // denoting validity.
 func (v AppVersion) Version() (string, bool) { return v.version, v.versionValid }
 
-// CommitHash returns the parsed full commit hash bytes and validity.
-func (v AppVersion) CommitHash() ([sha1.Size]byte, bool) { return v.commit, v.commitValid }
+// Compare returns -1, 0 or +1 depending on whether v is lower, equal or
+// greater than other, following semantic versioning precedence (see
+// [semver.Compare]). Commit hash and build date are not compared.
+//
+// Invalid versions sort lower than any valid one. Two invalid versions are
+// ordered by their raw strings, so sorting stays deterministic.
+func (v AppVersion) Compare(other AppVersion) int {
+	switch {
+	case v.versionValid && other.versionValid:
+		return semver.Compare(v.version, other.version)
+	case v.versionValid:
+		return 1
+	case other.versionValid:
+		return -1
+	default:
+		return strings.Compare(v.versionRaw, other.versionRaw)
+	}
+}
+
+// Before reports whether v is lower than other, see [AppVersion.Compare].
+func (v AppVersion) Before(other AppVersion) bool { return v.Compare(other) < 0 }
+
+// After reports whether v is greater than other, see [AppVersion.Compare].
+func (v AppVersion) After(other AppVersion) bool { return v.Compare(other) > 0 }
+
+// CommitHash returns the parsed commit hash bytes and validity. Both SHA-1
+// (20 bytes) and SHA-256 (32 bytes) object hashes are supported, as well as
+// abbreviated hashes of at least [MinShortCommitLength] hex digits. For odd
+// length abbreviations only complete bytes are returned.
+func (v AppVersion) CommitHash() ([]byte, bool) { return slices.Clone(v.commit), v.commitValid }
 
 // Date returns the parsed build timestamp and validity.
 func (v AppVersion) Date() (time.Time, bool) { return v.date, v.dateValid }
@@ -118,27 +213,26 @@ func (v AppVersion) Date() (time.Time, bool) { return v.date, v.dateValid }
 // More info: https://github.com/semver/semver/issues/614
 func (v AppVersion) VersionCommit() (res string, valid bool) {
 	version, versionValid := v.Version()
-	commitShort, commitValid := v.ShortHash()
 
-	return version + "#" + hex.EncodeToString(commitShort[:]), versionValid && commitValid
+	return version + "#" + v.shortHex(), versionValid && v.commitValid
 }
 
 // String returns a human-friendly composite string including raw version,
-// short commit hash (or raw commit when invalid) and build date (parsed or
-// raw). This is suitable for logging.
+// short commit hash (or raw commit when invalid) and build date (formatted with
+// the layout it was parsed with, or raw). This is suitable for logging.
 func (v AppVersion) String() (res string) {
 	res += v.versionRaw
 	res += "-"
 
-	if hashShort, ok := v.ShortHash(); ok {
-		res += hex.EncodeToString(hashShort[:])
+	if v.commitValid {
+		res += v.shortHex()
 	} else {
 		res += v.commitRaw
 	}
 
 	res += "-"
 	if date, ok := v.Date(); ok {
-		res += date.Format(DefaultDateFormat)
+		res += formatDate(date, v.dateLayout)
 	} else {
 		res += v.dateRaw
 	}
@@ -147,30 +241,119 @@ func (v AppVersion) String() (res string) {
 }
 
 // ShortHash extracts the first 7 bytes of the commit hash along with validity.
+// Bytes missing in abbreviated hashes are left zero.
 func (v AppVersion) ShortHash() (short [7]byte, valid bool) {
-	copy(short[:], v.commit[:7])
+	copy(short[:], v.commit)
 
 	return short, v.commitValid
 }
 
+// shortHex returns up to 7 bytes of the commit hash as hex, exactly as much
+// as was provided on build.
+func (v AppVersion) shortHex() string {
+	const shortLen = 7 * 2
+
+	return v.commitHex[:min(len(v.commitHex), shortLen)]
+}
+
+type versionJSON struct {
+	Version     string `json:"version"`
+	Commit      string `json:"commit"`
+	ShortCommit string `json:"short_commit"`
+	Date        string `json:"date"`
+	Valid       bool   `json:"valid"`
+}
+
+var (
+	_ json.Marshaler   = AppVersion{}
+	_ json.Unmarshaler = (*AppVersion)(nil)
+)
+
+// MarshalJSON implements [json.Marshaler]. Invalid components are serialized
+// with their raw values, and "valid" is set to false, instead of failing.
+func (v AppVersion) MarshalJSON() ([]byte, error) {
+	res := versionJSON{
+		Version:     v.versionRaw,
+		Commit:      v.commitRaw,
+		ShortCommit: v.commitRaw,
+		Date:        v.dateRaw,
+		Valid:       v.Valid(),
+	}
+
+	if version, ok := v.Version(); ok {
+		res.Version = version
+	}
+
+	if v.commitValid {
+		res.Commit = v.commitHex
+		res.ShortCommit = v.shortHex()
+	}
+
+	if date, ok := v.Date(); ok {
+		res.Date = date.Format(DefaultDateFormat)
+	}
+
+	//nolint:wrapcheck // plain struct, can't fail.
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. Value is rebuilt with
+// [NewVersion], so "short_commit" and "valid" fields are ignored.
+func (v *AppVersion) UnmarshalJSON(data []byte) error {
+	var raw versionJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("unmarshaling version: %w", err)
+	}
+
+	*v = NewVersion(raw.Version, raw.Commit, raw.Date)
+
+	return nil
+}
+
 func buildVersion(v string) (version string, valid bool) {
 	return v, semver.IsValid(v)
 }
 
-func buildCommitHash(h string) (hash [sha1.Size]byte, valid bool) {
-	data, err := hex.DecodeString(h)
-	if err != nil {
-		copy(hash[:], h)
-		return hash, false
+func buildCommitHash(h string) (hash []byte, hexHash string, valid bool) {
+	h = strings.ToLower(h)
+
+	switch l := len(h); {
+	case l >= MinShortCommitLength && l <= sha1.Size*2, l == sha256.Size*2:
+	default:
+		return nil, "", false
 	}
 
-	copy(hash[:], data)
+	// odd length abbreviations: decoding complete bytes only.
+	data, err := hex.DecodeString(h[:len(h)/2*2])
+	if err != nil || strings.Trim(h[len(h)/2*2:], hexDigits) != "" {
+		return nil, "", false
+	}
+
+	return data, h, true
+}
 
-	return hash, len(data) == sha1.Size
+func formatDate(date time.Time, layout string) string {
+	switch layout {
+	case "":
+		return date.Format(DefaultDateFormat)
+	case UnixDateFormat:
+		return strconv.FormatInt(date.Unix(), 10)
+	default:
+		return date.Format(layout)
+	}
 }
 
-func buildDate(d string) (date time.Time, valid bool) {
-	date, err := time.Parse(DefaultDateFormat, d)
+func buildDate(d, layout string) (date time.Time, valid bool) {
+	if layout == UnixDateFormat {
+		sec, err := strconv.ParseInt(d, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return time.Unix(sec, 0).UTC(), true
+	}
+
+	date, err := time.Parse(layout, d)
 	if err != nil {
 		return time.Time{}, false
 	}
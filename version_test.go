@@ -0,0 +1,261 @@
+package core_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/quenbyako/core"
+)
+
+func TestVersionCommitHash(t *testing.T) {
+	t.Parallel()
+
+	const (
+		sha1Hash   = "0123456789abcdef0123456789abcdef01234567"
+		sha256Hash = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	)
+
+	for _, tt := range []struct {
+		name     string
+		commit   string
+		valid    bool
+		wantHash string // complete bytes of the hash, as hex
+		short    string
+	}{
+		{"sha1", sha1Hash, true, sha1Hash, sha1Hash[:14]},
+		{"sha256", sha256Hash, true, sha256Hash, sha256Hash[:14]},
+		{"short", "abc1234", true, "abc123", "abc1234"},
+		{"short uppercase", "ABC1234", true, "abc123", "abc1234"},
+		{"medium", sha1Hash[:16], true, sha1Hash[:16], sha1Hash[:14]},
+		{"too short", "abc123", false, "", ""},
+		{"too long", sha1Hash + "0", false, "", ""},
+		{"non-hex short", "abc123z", false, "", ""},
+		{"invalid hex", "not-a-commit-hash", false, "", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := NewVersion("v1.2.3", tt.commit, "2025-01-02T03:04:05Z")
+
+			hash, valid := v.CommitHash()
+			if valid != tt.valid {
+				t.Fatalf("expected commit validity %v, got %v", tt.valid, valid)
+			}
+
+			if v.Valid() != tt.valid {
+				t.Errorf("expected version validity %v, got %v", tt.valid, v.Valid())
+			}
+
+			if !tt.valid {
+				if !strings.Contains(v.String(), tt.commit) {
+					t.Errorf("expected %q to contain raw commit %q", v.String(), tt.commit)
+				}
+
+				return
+			}
+
+			if want, _ := hex.DecodeString(tt.wantHash); !bytes.Equal(hash, want) {
+				t.Errorf("expected hash %x, got %x", want, hash)
+			}
+
+			short, _ := v.ShortHash()
+			if want, _ := hex.DecodeString(tt.wantHash); !bytes.HasPrefix(short[:], want[:min(len(want), 7)]) {
+				t.Errorf("expected short hash to start with %x, got %x", want, short)
+			}
+
+			if got, _ := v.VersionCommit(); got != "v1.2.3#"+tt.short {
+				t.Errorf("unexpected version commit %q", got)
+			}
+
+			if got := v.String(); got != "v1.2.3-"+tt.short+"-2025-01-02T03:04:05Z" {
+				t.Errorf("unexpected string %q", got)
+			}
+		})
+	}
+}
+
+func TestVersionJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		v := NewVersion("v1.2.3", "0123456789abcdef0123456789abcdef01234567", "2025-01-02T03:04:05Z")
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"version":"v1.2.3","commit":"0123456789abcdef0123456789abcdef01234567",` +
+			`"short_commit":"0123456789abcd","date":"2025-01-02T03:04:05Z","valid":true}`
+		if string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+
+		var got AppVersion
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.String() != v.String() || !got.Valid() {
+			t.Errorf("round trip mismatch: %v != %v", got, v)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(NewVersion("1.2", "dirty", "yesterday"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const want = `{"version":"1.2","commit":"dirty","short_commit":"dirty","date":"yesterday","valid":false}`
+		if string(data) != want {
+			t.Errorf("expected %s, got %s", want, data)
+		}
+	})
+}
+
+func TestVersionCompare(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.2.4", -1},
+		{"v1.10.0", "v1.9.0", 1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v1.0.0-alpha", "v1.0.0", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-alpha.1", "v1.0.0-beta", -1},
+		{"v1.0.0-beta.2", "v1.0.0-beta.11", -1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0+build.1", "v1.0.0+build.2", 0},
+		{"v1.2", "v1.2.0", 0},
+		{"broken", "v0.0.1", -1},
+		{"v0.0.1", "broken", 1},
+		{"a-broken", "b-broken", -1},
+		{"broken", "broken", 0},
+	} {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			t.Parallel()
+
+			a := NewVersion(tt.a, "", "")
+			b := NewVersion(tt.b, "", "")
+
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+
+			if got := a.Before(b); got != (tt.want < 0) {
+				t.Errorf("Before: expected %v, got %v", tt.want < 0, got)
+			}
+
+			if got := a.After(b); got != (tt.want > 0) {
+				t.Errorf("After: expected %v, got %v", tt.want > 0, got)
+			}
+		})
+	}
+}
+
+func TestVersionFromBuildInfo(t *testing.T) {
+	t.Parallel()
+
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+
+	t.Run("stamped", func(t *testing.T) {
+		t.Parallel()
+
+		v := VersionFromBuildInfoData(&debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/app", Version: "v1.4.0"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs", Value: "git"},
+				{Key: "vcs.revision", Value: commit},
+				{Key: "vcs.time", Value: "2025-01-02T03:04:05Z"},
+			},
+		})
+
+		if got := v.String(); got != "v1.4.0-0123456789abcd-2025-01-02T03:04:05Z" {
+			t.Errorf("unexpected version %q", got)
+		}
+
+		if !v.Valid() {
+			t.Error("expected valid version")
+		}
+	})
+
+	t.Run("unstamped", func(t *testing.T) {
+		t.Parallel()
+
+		v := VersionFromBuildInfoData(&debug.BuildInfo{
+			Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+		})
+
+		if want := NewVersion(DefaultVersion, DefaultCommit, DefaultDate); v.String() != want.String() {
+			t.Errorf("expected defaults %q, got %q", want, v)
+		}
+	})
+}
+
+func TestVersionDateFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name, date, layout string
+		want               time.Time
+		valid              bool
+	}{
+		{"rfc3339", "2025-01-02T03:04:05Z", time.RFC3339, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"empty layout", "2025-01-02T03:04:05Z", "", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"custom layout", "2025-01-02 03:04", "2006-01-02 15:04", time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC), true},
+		{"unix", "1735787045", UnixDateFormat, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"unix invalid", "2025-01-02", UnixDateFormat, time.Time{}, false},
+		{"layout mismatch", "1735787045", time.RFC3339, time.Time{}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			date, valid := NewVersionWithDateFormat("v1.0.0", "", tt.date, tt.layout).Date()
+			if valid != tt.valid {
+				t.Fatalf("expected validity %v, got %v", tt.valid, valid)
+			}
+
+			if !date.Equal(tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, date)
+			}
+		})
+	}
+}
+
+func TestVersionStringDateFormat(t *testing.T) {
+	t.Parallel()
+
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+
+	for _, tt := range []struct {
+		name, date, layout, want string
+	}{
+		{"default", "2025-01-02T03:04:05Z", "", "v1.0.0-0123456789abcd-2025-01-02T03:04:05Z"},
+		{"unix", "1735787045", UnixDateFormat, "v1.0.0-0123456789abcd-1735787045"},
+		{"unix date", "Thu Jan  2 03:04:05 UTC 2025", time.UnixDate, "v1.0.0-0123456789abcd-Thu Jan  2 03:04:05 UTC 2025"},
+		{"date only", "2025-01-02", time.DateOnly, "v1.0.0-0123456789abcd-2025-01-02"},
+		{"invalid", "2025/01/02", time.DateOnly, "v1.0.0-0123456789abcd-2025/01/02"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NewVersionWithDateFormat("v1.0.0", commit, tt.date, tt.layout).String(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}